@@ -0,0 +1,135 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// announceInterval is the minimum time between two announcements of a
+// changed suffix in accessible mode. It is a variable so tests don't
+// have to wait.
+var announceInterval = 2 * time.Second
+
+// WithAccessible enables or disables accessible mode, for screen readers.
+// In accessible mode nothing is animated and the cursor is left alone:
+// the spinner writes a line when it starts, e.g. "Started: Building",
+// when the suffix changes, at most every couple of seconds, and when it
+// stops, e.g. "Finished: Building, 12 seconds". The lines are written
+// whether or not the Writer is a terminal. Accessible mode is enabled by
+// default when SPINNER_ACCESSIBLE or ACCESSIBLE is set to a true value,
+// e.g. 1, true, yes or on. Other values are ignored.
+func WithAccessible(enabled bool) Option {
+	return func(s *Spinner) {
+		s.accessible = enabled
+	}
+}
+
+// accessibleFromEnv returns whether accessible mode is requested by the
+// environment, parsed like the SPINNER_ACCESSIBLE variable of WithEnv.
+func accessibleFromEnv() bool {
+	for _, name := range []string{"SPINNER_ACCESSIBLE", "ACCESSIBLE"} {
+		if accessible, err := parseBool(os.Getenv(name)); err == nil && accessible {
+			return true
+		}
+	}
+	return false
+}
+
+// status returns the text announced in accessible mode: the suffix and
+// progress. Caller must already hold s.lock.
+func (s *Spinner) status() string {
+	return strings.TrimSpace(s.suffix())
+}
+
+// announce writes a line made of the given label and text, leaving out
+// whichever is empty. Caller must already hold s.lock.
+func (s *Spinner) announce(label, text string) {
+	switch {
+	case label == "":
+		s.buf.WriteString(text + "\n")
+	case text == "":
+		s.buf.WriteString(label + "\n")
+	default:
+		s.buf.WriteString(label + ": " + text + "\n")
+	}
+}
+
+// statusLabels holds the label announcing each status in accessible mode
+var statusLabels = map[Status]string{
+	Success: "Finished",
+	Failure: "Failed",
+	Warning: "Warning",
+	Skipped: "Skipped",
+}
+
+// announceStatus announces the outcome of a step, the spoken counterpart
+// of statusLine, e.g. "Failed: Deploying: connection refused, 2 seconds".
+// Caller must already hold s.lock.
+func (s *Spinner) announceStatus(st Status, msg string, err error, elapsed time.Duration) {
+	if err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, err)
+	}
+	if st != Skipped {
+		msg += ", " + spokenDuration(elapsed)
+	}
+	s.announce(statusLabels[st], msg)
+}
+
+// announceChanges announces the suffix whenever it changed, at most every
+// interval, until stop is closed.
+func (s *Spinner) announceChanges(stop chan struct{}, interval time.Duration) {
+	for wait(stop, interval) {
+		s.mu.Lock()
+		if !s.active.get() || s.stopChan != stop {
+			s.mu.Unlock()
+			return
+		}
+		if st := s.status(); !s.paused && st != s.announced {
+			s.announced = st
+			s.announce("", st)
+			s.flush()
+		}
+		s.mu.Unlock()
+		s.dispatch()
+	}
+}
+
+// spokenDuration formats d to be read out, e.g. "1 minute 5 seconds".
+func spokenDuration(d time.Duration) string {
+	secs := int(d.Round(time.Second) / time.Second)
+	if secs < 1 {
+		return "less than a second"
+	}
+	if secs < 60 {
+		return plural(secs, "second")
+	}
+	text := plural(secs/60, "minute")
+	if secs%60 > 0 {
+		text += " " + plural(secs%60, "second")
+	}
+	return text
+}
+
+// plural returns n followed by unit, in the plural unless n is 1.
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withAnnounceInterval sets announceInterval until the test completes
+func withAnnounceInterval(t *testing.T, d time.Duration) {
+	interval := announceInterval
+	announceInterval = d
+	t.Cleanup(func() { announceInterval = interval })
+}
+
+// TestAccessibleLines verifies that accessible mode writes complete lines
+// for the start and stop of the spinner, and nothing else
+func TestAccessibleLines(t *testing.T) {
+	var out syncBuffer
+	s := New(CharSets[14], 10*time.Millisecond, WithAccessible(true), WithWriter(&out), WithSuffix(" Building"))
+
+	s.Start()
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+
+	want := "Started: Building\nFinished: Building, less than a second\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestAccessibleSuffixUpdates verifies that changes of the suffix are
+// announced at most every announceInterval
+func TestAccessibleSuffixUpdates(t *testing.T) {
+	withAnnounceInterval(t, 50*time.Millisecond)
+	var out syncBuffer
+	s := New(CharSets[14], 10*time.Millisecond, WithAccessible(true), WithWriter(&out))
+
+	s.Start()
+	for i := 0; i < 10; i++ {
+		s.Lock()
+		s.Suffix = fmt.Sprintf(" step %d", i)
+		s.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(120 * time.Millisecond)
+	s.Stop()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) < 3 || lines[0] != "Started" || lines[len(lines)-1] != "Finished: step 9, less than a second" {
+		t.Fatalf("unexpected output %q", lines)
+	}
+	updates := lines[1 : len(lines)-1]
+	if len(updates) > 3 || updates[len(updates)-1] != "step 9" {
+		t.Errorf("got updates %q, want at most 3 ending with the last suffix", updates)
+	}
+}
+
+// TestAccessibleRun verifies that in accessible mode the outcome of Run
+// is announced once, in place of the generic stop line and status line
+func TestAccessibleRun(t *testing.T) {
+	var out syncBuffer
+	s := New(CharSets[14], 10*time.Millisecond, WithAccessible(true), WithWriter(&out))
+
+	s.Run(context.Background(), "Deploying", func(context.Context) error {
+		return errors.New("boom")
+	})
+	s.Run(context.Background(), "Rolling back", func(context.Context) error { return nil })
+
+	want := "Started: Deploying\n" +
+		"Failed: Deploying: boom, less than a second\n" +
+		"Started: Rolling back\n" +
+		"Finished: Rolling back, less than a second\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestAccessiblePersist verifies that in accessible mode Persist announces
+// the outcome of the step instead of writing a status line
+func TestAccessiblePersist(t *testing.T) {
+	var out syncBuffer
+	s := New(CharSets[14], 10*time.Millisecond, WithAccessible(true), WithWriter(&out))
+
+	s.Persist(Skipped, "Checked config")
+	s.Start()
+	s.Persist(Success, "Fetched deps")
+	s.Persist(Warning, "Compiled")
+	s.Stop()
+
+	want := "Skipped: Checked config\n" +
+		"Started\n" +
+		"Finished: Fetched deps, less than a second\n" +
+		"Warning: Compiled, less than a second\n" +
+		"Finished: less than a second\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestAccessibleTaskGroup verifies that in accessible mode the tasks
+// aren't animated, only their start and outcome are announced, followed
+// by the summary
+func TestAccessibleTaskGroup(t *testing.T) {
+	var out syncBuffer
+	s := New(CharSets[14], 10*time.Millisecond, WithAccessible(true), WithWriter(&out))
+	g := NewTaskGroup(s)
+
+	nginx, redis := make(chan struct{}), make(chan struct{})
+	g.Go("pull nginx", func() error {
+		<-nginx
+		return nil
+	})
+	time.Sleep(50 * time.Millisecond)
+	g.Go("pull redis", func() error {
+		<-redis
+		return errors.New("manifest unknown")
+	})
+	time.Sleep(50 * time.Millisecond)
+	close(redis)
+	time.Sleep(50 * time.Millisecond)
+	close(nginx)
+	g.Wait()
+
+	want := "Started: pull nginx\n" +
+		"Started: pull redis\n" +
+		"Failed: pull redis: manifest unknown, less than a second\n" +
+		"Finished: pull nginx, less than a second\n" +
+		"2/2 done, 1 failed\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestAccessibleTree verifies that in accessible mode each step is
+// announced when it starts and completes
+func TestAccessibleTree(t *testing.T) {
+	var out syncBuffer
+	s := New(CharSets[14], 10*time.Millisecond, WithAccessible(true), WithWriter(&out))
+	tree := NewTree(s)
+
+	build := tree.Step("Build")
+	time.Sleep(50 * time.Millisecond)
+	// holds the announcements until the steps are completed
+	s.Lock()
+	build.Step("Compile").Done(nil)
+	build.Step("Lint").Skip()
+	build.Done(nil)
+	s.Unlock()
+	tree.Stop()
+
+	want := "Started: Build\n" +
+		"Finished: Compile, less than a second\n" +
+		"Skipped: Lint\n" +
+		"Finished: Build, less than a second\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestAccessibleFromEnv verifies that accessible mode is enabled by the
+// environment
+func TestAccessibleFromEnv(t *testing.T) {
+	tests := []struct {
+		spinner, generic string
+		want             bool
+	}{
+		{"", "", false},
+		{"1", "", true},
+		{"", "true", true},
+		{"0", "", false},
+		{"false", "", false},
+		{"off", "", false},
+		{"", "No", false},
+		{"yes", "", true},
+		{"maybe", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("SPINNER_ACCESSIBLE", tt.spinner)
+		t.Setenv("ACCESSIBLE", tt.generic)
+		s := New(CharSets[14], time.Second)
+		if s.accessible != tt.want || s.HideCursor == tt.want {
+			t.Errorf("SPINNER_ACCESSIBLE=%q ACCESSIBLE=%q: got accessible %t", tt.spinner, tt.generic, s.accessible)
+		}
+	}
+}
+
+// TestSpokenDuration verifies the durations announced in accessible mode
+func TestSpokenDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{200 * time.Millisecond, "less than a second"},
+		{time.Second, "1 second"},
+		{12 * time.Second, "12 seconds"},
+		{time.Minute, "1 minute"},
+		{125 * time.Second, "2 minutes 5 seconds"},
+	}
+
+	for _, tt := range tests {
+		if got := spokenDuration(tt.d); got != tt.want {
+			t.Errorf("spokenDuration(%s): got %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import "time"
+
+// block animates several lines at once using the character set, color,
+// delay, writer and erase logic of a Spinner. The lines are produced by
+// render, which is given the colored frame to show next to running items.
+// render is called with the spinner locked and must not call its methods.
+// In accessible mode nothing is animated: announce is called instead to
+// announce the items whose status changed since its previous call.
+type block struct {
+	s        *Spinner
+	render   func(frame string) string
+	announce func()
+	active   bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newBlock returns a block drawing the lines produced by render with s,
+// or announcing changes with announce in accessible mode. Both are called
+// with the spinner locked.
+func newBlock(s *Spinner, render func(frame string) string, announce func()) *block {
+	return &block{
+		s:        s,
+		render:   render,
+		announce: announce,
+	}
+}
+
+// start starts animating the lines if writing to a terminal, or
+// announcing changes in accessible mode. A block may be started again
+// once finished. Like a spinner, the block stops drawing once its Writer
+// is closed.
+func (b *block) start() {
+	s := b.s
+	s.mu.Lock()
+	if !b.active {
+		s.err = nil
+	}
+	if b.active || !s.enabled.get() || !s.accessible && !isRunningInTerminal(s) {
+		s.mu.Unlock()
+		return
+	}
+	if s.HideCursor && !s.accessible && !isWindowsTerminalOnWindows {
+		// hides the cursor
+		s.buf.WriteString("\033[?25l")
+		s.flush()
+	}
+	b.active = true
+	// each run gets its own channels so a finished block can be restarted
+	stop, done := make(chan struct{}), make(chan struct{})
+	b.stop, b.done = stop, done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			s.mu.Lock()
+			if isClosedWriterError(s.err) {
+				s.mu.Unlock()
+				return
+			}
+			if s.accessible {
+				b.announce()
+				s.flush()
+			} else if len(s.chars) > 0 {
+				i %= len(s.chars)
+				s.erase()
+				s.lastOutputPlain = b.render(s.colorFrame(s.chars[i]))
+				s.buf.WriteString(s.lastOutputPlain)
+				s.flush()
+			}
+			delay := s.frameDelay(i)
+			s.mu.Unlock()
+			s.dispatch()
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+}
+
+// finish stops the animation and replaces the lines with final, which is
+// left on screen. In accessible mode the remaining changes are announced
+// before final.
+func (b *block) finish(final string) {
+	s := b.s
+	s.mu.Lock()
+	active, stop, done := b.active, b.stop, b.done
+	b.active = false
+	s.mu.Unlock()
+	if active {
+		close(stop)
+		<-done
+	}
+
+	defer s.dispatch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if isClosedWriterError(s.err) {
+		// nothing can be written anymore
+		return
+	}
+	if active && !s.accessible {
+		s.erase()
+		if s.HideCursor && !isWindowsTerminalOnWindows {
+			// makes the cursor visible
+			s.buf.WriteString("\033[?25h")
+		}
+	}
+	if s.accessible {
+		b.announce()
+	}
+	s.buf.WriteString(final)
+	s.flush()
+}
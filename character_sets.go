@@ -14,6 +14,15 @@
 
 package spinner
 
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// ErrUnknownCharSet is returned when looking up a character set that doesn't exist
+var ErrUnknownCharSet = errors.New("unknown character set")
+
 const (
 	clockOneOClock = '\U0001F550'
 	clockOneThirty = '\U0001F55C'
@@ -113,6 +122,58 @@ var CharSets = map[int][]string{
 	90: {"↞", "↟", "↠", "↡"},
 }
 
+// charSetNames maps well known names to the index of the character set in CharSets.
+var charSetNames = map[string]int{
+	"arrow":           0,
+	"grow-vertical":   1,
+	"triangle":        4,
+	"square-corners":  5,
+	"circle-quarters": 6,
+	"circle-halves":   7,
+	"line":            9,
+	"dots2":           11,
+	"fish":            12,
+	"dots":            14,
+	"alphabet":        15,
+	"grow-horizontal": 16,
+	"simple-dots":     26,
+	"clock":           37,
+	"earth":           39,
+	"arc":             40,
+	"bouncing-ball":   52,
+	"star":            53,
+	"dqpb":            68,
+	"moon":            70,
+}
+
+// CharSetNames returns the sorted names that can be given to LookupCharSet.
+func CharSetNames() []string {
+	names := make([]string, 0, len(charSetNames))
+	for name := range charSetNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupCharSet returns the character set with the given name, e.g. "dots",
+// or the given index in CharSets, e.g. "14".
+func LookupCharSet(name string) ([]string, error) {
+	i, ok := charSetNames[name]
+	if !ok {
+		n, err := strconv.Atoi(name)
+		if err != nil {
+			return nil, ErrUnknownCharSet
+		}
+		i = n
+	}
+	cs, ok := CharSets[i]
+	if !ok {
+		return nil, ErrUnknownCharSet
+	}
+	return cs, nil
+}
+
 func init() {
 	for i := rune(0); i < 12; i++ {
 		CharSets[37] = append(CharSets[37], string([]rune{clockOneOClock + i}))
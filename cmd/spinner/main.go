@@ -0,0 +1,212 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command spinner runs a command and shows a spinner on stderr until it
+// finishes.
+//
+//	spinner --charset dots --suffix " Building" -- make build
+//
+// The command's output is captured and printed if it fails, or streamed
+// with --stream. When the command finishes a status line with its
+// duration is printed and spinner exits with the command's exit code.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+)
+
+const usage = `usage: spinner [flags] -- command [args...]
+
+Runs the command and shows a spinner on stderr until it finishes.
+
+flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the command given in args and returns the exit code.
+func run(args []string) int {
+	fs := flag.NewFlagSet("spinner", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\ncharsets: %s or an index between 0 and %d\n",
+			strings.Join(spinner.CharSetNames(), ", "), len(spinner.CharSets)-1)
+	}
+	charset := fs.String("charset", "dots", "character set name or index")
+	delay := fs.Duration("delay", 100*time.Millisecond, "delay between frames")
+	spinnerColor := fs.String("color", "", "color of the spinner, e.g. cyan or fgHiGreen,bold")
+	prefix := fs.String("prefix", "", "text shown before the spinner")
+	suffix := fs.String("suffix", "", "text shown after the spinner")
+	successMsg := fs.String("success-msg", "", "message printed when the command succeeds (default: the command)")
+	failureMsg := fs.String("failure-msg", "", "message printed when the command fails (default: the command)")
+	stream := fs.Bool("stream", false, "stream the command's output instead of printing it on failure")
+	showOutput := fs.Bool("show-output", false, "show the last line of the command's output as the suffix")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cs, err := spinner.LookupCharSet(*charset)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "spinner: %s: %v\n", *charset, err)
+		return 2
+	}
+	s := spinner.New(cs, *delay, spinner.WithWriterFile(os.Stderr))
+	s.Prefix = *prefix
+	s.Suffix = *suffix
+	if *spinnerColor != "" {
+		if err := s.Color(strings.Split(*spinnerColor, ",")...); err != nil {
+			fmt.Fprintf(os.Stderr, "spinner: %s: %v\n", *spinnerColor, err)
+			return 2
+		}
+	}
+
+	var captured outputBuffer
+	var stdout, stderr io.Writer = &captured, &captured
+	if *stream {
+		stdout = &streamWriter{s: s, w: os.Stdout}
+		stderr = &streamWriter{s: s, w: os.Stderr}
+	}
+	tracker := spinner.NewOutputTracker(s, nil)
+	if *showOutput {
+		stdout = io.MultiWriter(stdout, tracker)
+		stderr = io.MultiWriter(stderr, tracker)
+	}
+
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// the command receives interrupts from the terminal itself, keep
+	// running so the cursor is restored and the exit code reported. The
+	// interrupts are caught rather than ignored, as the command would
+	// inherit ignoring them.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		for range interrupts {
+		}
+	}()
+
+	start := time.Now()
+	s.Start()
+	err = cmd.Run()
+	signal.Stop(interrupts)
+	close(interrupts)
+	tracker.Restore()
+	s.Stop()
+	elapsed := time.Since(start).Round(10 * time.Millisecond)
+
+	desc := strings.Join(fs.Args(), " ")
+	code := exitCode(err)
+	if code == 0 {
+		if *successMsg != "" {
+			desc = *successMsg
+		}
+		fmt.Fprintf(os.Stderr, "%s %s (%s)\n", color.GreenString("✔"), desc, elapsed)
+		return 0
+	}
+
+	if *failureMsg != "" {
+		desc = *failureMsg
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		fmt.Fprintf(os.Stderr, "%s %s (exit %d, %s)\n", color.RedString("✖"), desc, code, elapsed)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", color.RedString("✖"), desc, err)
+	}
+	os.Stderr.Write(captured.Bytes())
+	return code
+}
+
+// exitCode returns the exit code to report for the error returned by
+// running the command.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code
+		}
+		return 1 // terminated by a signal
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return 127
+	}
+	return 126
+}
+
+// outputBuffer holds the command's output written from stdout and
+// stderr concurrently.
+type outputBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write appends p to the buffer.
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// Bytes returns the captured output.
+func (b *outputBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+// streamWriter writes the command's output above the spinner line.
+type streamWriter struct {
+	s *spinner.Spinner
+	w io.Writer
+}
+
+// Write clears the spinner line and writes p, the spinner redraws the
+// line on its next frame.
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	sw.s.Lock()
+	defer sw.s.Unlock()
+	if sw.s.Active() {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	return sw.w.Write(p)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// TestRunExitCode verifies that the exit code of the command is passed through
+func TestRunExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	tests := []struct {
+		description string
+		args        []string
+		expected    int
+	}{
+		{"Success", []string{"--", "sh", "-c", "exit 0"}, 0},
+		{"Failure", []string{"--charset", "9", "--", "sh", "-c", "exit 3"}, 3},
+		{"NotFound", []string{"--", "spinner-no-such-command"}, 127},
+		{"UnknownCharSet", []string{"--charset", "nope", "--", "true"}, 2},
+		{"InvalidColor", []string{"--color", "bluez", "--", "true"}, 2},
+		{"NoCommand", []string{"--suffix", "x"}, 2},
+	}
+
+	for _, test := range tests {
+		if code := run(test.args); code != test.expected {
+			t.Errorf("%s: expected exit code %d, got %d", test.description, test.expected, code)
+		}
+	}
+}
+
+// TestCommandReceivesInterrupts verifies that the command doesn't inherit
+// ignoring interrupts, so it can still be stopped with Ctrl-C
+func TestCommandReceivesInterrupts(t *testing.T) {
+	if _, err := os.Stat("/proc/self/status"); err != nil {
+		t.Skip("/proc not available")
+	}
+	// SIGINT is the second bit of the ignored signals mask
+	script := `case $(grep SigIgn /proc/$$/status) in *[2367abef]) exit 1;; esac`
+	if code := run([]string{"--", "sh", "-c", script}); code != 0 {
+		t.Errorf("the command ignores SIGINT, exit code %d", code)
+	}
+}
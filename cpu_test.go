@@ -0,0 +1,68 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin
+// +build linux darwin
+
+package spinner
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+// cpuTime returns the CPU time used by the process so far
+func cpuTime(t *testing.T) time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		t.Fatal(err)
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
+
+// TestEmptyCharSetCPU verifies that a spinner without frames and with a
+// zero delay doesn't busy loop
+func TestEmptyCharSetCPU(t *testing.T) {
+	withTerminal(t)
+	tests := []struct {
+		name  string
+		start func() *Spinner
+	}{
+		{"New(nil, 0)", func() *Spinner {
+			s, _ := withOutput(nil, 0)
+			s.Start()
+			return s
+		}},
+		{"UpdateCharSet([]string{})", func() *Spinner {
+			s, _ := withOutput(CharSets[9], 0)
+			s.Start()
+			s.UpdateCharSet([]string{})
+			return s
+		}},
+	}
+
+	for _, tt := range tests {
+		const wall = 200 * time.Millisecond
+		before := cpuTime(t)
+		s := tt.start()
+		time.Sleep(wall)
+		s.Stop()
+
+		// a busy loop would use about as much CPU time as wall time
+		if used := cpuTime(t) - before; used > wall/2 {
+			t.Errorf("%s: used %s of CPU time in %s", tt.name, used, wall)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDelay is returned for a delay that isn't a positive duration
+var ErrInvalidDelay = errors.New("invalid delay")
+
+// WithEnv configures the spinner from the following environment
+// variables, named after the given prefix, "SPINNER" if empty:
+//
+//	SPINNER_CHARSET     name or index of the character set, see LookupCharSet
+//	SPINNER_DELAY       delay between frames, e.g. 80ms
+//	SPINNER_COLOR       comma separated colors, e.g. cyan,bold
+//	SPINNER_DISABLE     disables the spinner when true, e.g. 1
+//	SPINNER_ACCESSIBLE  enables accessible mode when true, see WithAccessible
+//
+// Variables that aren't set are ignored. Invalid values are ignored too
+// and reported by ConfigErr. Give WithEnv after the other options so the
+// environment overrides them.
+func WithEnv(prefix string) Option {
+	if prefix == "" {
+		prefix = "SPINNER"
+	}
+	return func(s *Spinner) {
+		env := func(name string, apply func(v string) error) {
+			name = prefix + "_" + name
+			if v := os.Getenv(name); v != "" {
+				if err := apply(v); err != nil {
+					s.setConfigErr(fmt.Errorf("%s=%q: %w", name, v, err))
+				}
+			}
+		}
+
+		env("CHARSET", func(v string) error {
+			cs, err := LookupCharSet(v)
+			if err != nil {
+				return err
+			}
+			s.chars, s.timing = cs, charSetTiming(cs)
+			return nil
+		})
+		env("DELAY", func(v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return ErrInvalidDelay
+			}
+			s.Delay = d
+			return nil
+		})
+		env("COLOR", func(v string) error {
+			fn, err := colorFunc(strings.Split(v, ","))
+			if err != nil {
+				return err
+			}
+			s.color = fn
+			return nil
+		})
+		env("DISABLE", func(v string) error {
+			disable, err := parseBool(v)
+			if err != nil {
+				return err
+			}
+			s.enabled.set(!disable)
+			return nil
+		})
+		env("ACCESSIBLE", func(v string) error {
+			accessible, err := parseBool(v)
+			if err != nil {
+				return err
+			}
+			s.accessible = accessible
+			return nil
+		})
+	}
+}
+
+// parseBool returns the boolean value of an environment variable. On top
+// of the values accepted by strconv.ParseBool, yes, no, on and off are
+// accepted in any case.
+func parseBool(v string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(v))
+}
+
+// ConfigErr returns the first error found while configuring the spinner,
+// e.g. an invalid color given to WithColor or an invalid value of an
+// environment variable read by WithEnv.
+func (s *Spinner) ConfigErr() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.configErr
+}
+
+// setConfigErr records err unless an error was already recorded.
+func (s *Spinner) setConfigErr(err error) {
+	if s.configErr == nil {
+		s.configErr = err
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithEnv verifies that the spinner is configured from the
+// environment
+func TestWithEnv(t *testing.T) {
+	t.Setenv("MYAPP_CHARSET", "dots")
+	t.Setenv("MYAPP_DELAY", "80ms")
+	t.Setenv("MYAPP_COLOR", "cyan,bold")
+	t.Setenv("MYAPP_DISABLE", "1")
+	t.Setenv("MYAPP_ACCESSIBLE", "off")
+
+	s := New(CharSets[9], time.Second, WithEnv("MYAPP"))
+	if err := s.ConfigErr(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.chars, CharSets[14]) {
+		t.Errorf("got character set %q, want %q", s.chars, CharSets[14])
+	}
+	if s.Delay != 80*time.Millisecond {
+		t.Errorf("got delay %s, want 80ms", s.Delay)
+	}
+	if s.Enabled() {
+		t.Error("spinner not disabled")
+	}
+	if s.accessible {
+		t.Error("accessible mode enabled")
+	}
+}
+
+// TestWithEnvInvalid verifies that invalid values are ignored and
+// reported by ConfigErr
+func TestWithEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name, value string
+		err         error
+	}{
+		{"SPINNER_CHARSET", "nope", ErrUnknownCharSet},
+		{"SPINNER_DELAY", "fast", ErrInvalidDelay},
+		{"SPINNER_DELAY", "-1s", ErrInvalidDelay},
+		{"SPINNER_COLOR", "cyan,nope", ErrInvalidColor},
+		{"SPINNER_DISABLE", "maybe", nil},
+		{"SPINNER_ACCESSIBLE", "maybe", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			s := New(CharSets[9], time.Second, WithEnv(""))
+
+			err := s.ConfigErr()
+			if err == nil || !strings.Contains(err.Error(), tt.name) {
+				t.Fatalf("got %v, want an error naming %s", err, tt.name)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("got %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(s.chars, CharSets[9]) || s.Delay != time.Second || !s.Enabled() {
+				t.Error("invalid value changed the spinner")
+			}
+		})
+	}
+}
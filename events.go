@@ -0,0 +1,127 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import "time"
+
+// EventType identifies a lifecycle transition of a spinner.
+type EventType int
+
+// Available event types
+const (
+	EventStart      EventType = iota // the spinner started
+	EventStop                        // the spinner stopped
+	EventRestart                     // the spinner was restarted
+	EventPause                       // the spinner was paused
+	EventResume                      // the spinner was resumed
+	EventStatus                      // a status line was written by Persist or Run
+	EventCharSet                     // the character set was changed
+	EventWriteError                  // writing to the Writer failed
+)
+
+// Event describes a lifecycle transition of a spinner. Only the fields
+// relevant to its type are set.
+type Event struct {
+	Type    EventType
+	Elapsed time.Duration // time since the spinner started, or the step for EventStatus
+	Message string        // FinalMSG for EventStop, the message of the status line for EventStatus
+	Status  Status        // status of the status line for EventStatus
+	CharSet []string      // new character set for EventCharSet
+	Err     error         // failed step for EventStatus, write error for EventWriteError and EventStop
+}
+
+// OnEvent registers fn to be called for every event. Listeners are called
+// without the spinner locked, so they may call its methods.
+func (s *Spinner) OnEvent(fn func(Event)) {
+	s.mu.Lock()
+	s.listeners = append(s.listeners, fn)
+	s.mu.Unlock()
+}
+
+// onEventType registers fn to be called for events of the given type.
+func (s *Spinner) onEventType(t EventType, fn func(Event)) {
+	s.OnEvent(func(e Event) {
+		if e.Type == t {
+			fn(e)
+		}
+	})
+}
+
+// OnStart registers fn to be called when the spinner starts.
+func (s *Spinner) OnStart(fn func()) {
+	s.onEventType(EventStart, func(Event) { fn() })
+}
+
+// OnStop registers fn to be called when the spinner stops. The event
+// holds the elapsed time and the final message written, or the write
+// error if the spinner stopped because its Writer was closed.
+func (s *Spinner) OnStop(fn func(Event)) {
+	s.onEventType(EventStop, fn)
+}
+
+// OnRestart registers fn to be called when the spinner is restarted.
+func (s *Spinner) OnRestart(fn func()) {
+	s.onEventType(EventRestart, func(Event) { fn() })
+}
+
+// OnPause registers fn to be called when the spinner is paused.
+func (s *Spinner) OnPause(fn func()) {
+	s.onEventType(EventPause, func(Event) { fn() })
+}
+
+// OnResume registers fn to be called when the spinner is resumed.
+func (s *Spinner) OnResume(fn func()) {
+	s.onEventType(EventResume, func(Event) { fn() })
+}
+
+// OnStatus registers fn to be called when a status line is written by
+// Persist or Run.
+func (s *Spinner) OnStatus(fn func(Event)) {
+	s.onEventType(EventStatus, fn)
+}
+
+// OnCharSetChange registers fn to be called with the new character set
+// when it is changed.
+func (s *Spinner) OnCharSetChange(fn func(cs []string)) {
+	s.onEventType(EventCharSet, func(e Event) { fn(e.CharSet) })
+}
+
+// OnWriteError registers fn to be called when writing to the Writer fails.
+func (s *Spinner) OnWriteError(fn func(err error)) {
+	s.onEventType(EventWriteError, func(e Event) { fn(e.Err) })
+}
+
+// emit queues the given event for the listeners. It is delivered by the
+// next call to dispatch. Caller must already hold s.lock.
+func (s *Spinner) emit(e Event) {
+	if len(s.listeners) > 0 {
+		s.events = append(s.events, e)
+	}
+}
+
+// dispatch calls the listeners for the queued events. Caller must not
+// hold s.lock.
+func (s *Spinner) dispatch() {
+	s.mu.Lock()
+	events, listeners := s.events, s.listeners
+	s.events = nil
+	s.mu.Unlock()
+
+	for _, e := range events {
+		for _, fn := range listeners {
+			fn(e)
+		}
+	}
+}
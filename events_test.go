@@ -0,0 +1,191 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestEvents verifies that listeners are called for lifecycle transitions
+func TestEvents(t *testing.T) {
+	withTerminal(t)
+	s, _ := withOutput(CharSets[9], time.Hour)
+	s.FinalMSG = "done\n"
+
+	var mu sync.Mutex
+	var types []EventType
+	var stop, status Event
+	s.OnEvent(func(e Event) {
+		mu.Lock()
+		types = append(types, e.Type)
+		mu.Unlock()
+	})
+	s.OnStop(func(e Event) { stop = e })
+	s.OnStatus(func(e Event) { status = e })
+	var charSet []string
+	s.OnCharSetChange(func(cs []string) { charSet = cs })
+
+	s.Start()
+	s.Pause()
+	s.Resume()
+	s.UpdateCharSet(CharSets[14])
+	s.Persist(Warning, "Fetched deps")
+	s.Restart()
+	s.Stop()
+
+	expected := []EventType{
+		EventStart, EventPause, EventResume, EventCharSet, EventStatus,
+		EventStop, EventStart, EventRestart, EventStop,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("unexpected events. got=%v want=%v", types, expected)
+	}
+	if stop.Message != "done\n" || stop.Elapsed <= 0 {
+		t.Errorf("unexpected stop event %+v", stop)
+	}
+	if status.Status != Warning || status.Message != "Fetched deps" {
+		t.Errorf("unexpected status event %+v", status)
+	}
+	if !reflect.DeepEqual(charSet, CharSets[14]) {
+		t.Errorf("unexpected character set %q", charSet)
+	}
+}
+
+// TestListenerCallsSpinner verifies that listeners may call the spinner
+// without deadlocking
+func TestListenerCallsSpinner(t *testing.T) {
+	withTerminal(t)
+	s, _ := withOutput(CharSets[9], 5*time.Millisecond)
+	s.OnStart(func() {
+		s.UpdateSpeed(10 * time.Millisecond)
+		s.Color("red")
+	})
+	stopped := make(chan struct{})
+	s.OnStop(func(Event) { close(stopped) })
+	s.OnCharSetChange(func([]string) { s.Stop() })
+
+	done := make(chan struct{})
+	go func() {
+		s.Start()
+		s.UpdateCharSet(CharSets[14])
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlock calling the spinner from a listener")
+	}
+	<-stopped
+	if s.Active() {
+		t.Error("expected the listener to stop the spinner")
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct {
+	err error
+}
+
+// Write
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+// TestOnWriteError verifies that write errors are reported
+func TestOnWriteError(t *testing.T) {
+	withTerminal(t)
+	errWrite := errors.New("write failed")
+	s := New(CharSets[9], 5*time.Millisecond, WithWriter(failingWriter{errWrite}))
+	reported := make(chan error, 100)
+	s.OnWriteError(func(err error) { reported <- err })
+
+	s.Start()
+	defer s.Stop()
+	select {
+	case err := <-reported:
+		if err != errWrite {
+			t.Errorf("expected the write error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the write error to be reported")
+	}
+}
+
+// TestStopOnClosedWriter verifies that the spinner stops itself once the
+// reader of its pipe went away
+func TestStopOnClosedWriter(t *testing.T) {
+	withTerminal(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	r.Close()
+
+	s := New(CharSets[9], 5*time.Millisecond, WithWriterFile(w))
+	stopped := make(chan Event, 1)
+	s.OnStop(func(e Event) { stopped <- e })
+	s.Start()
+
+	select {
+	case e := <-stopped:
+		if !errors.Is(e.Err, syscall.EPIPE) {
+			t.Errorf("expected a broken pipe in the stop event, got %v", e.Err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the spinner to stop itself")
+	}
+	if s.Active() {
+		t.Error("expected the spinner to be inactive")
+	}
+	if !errors.Is(s.Err(), syscall.EPIPE) {
+		t.Errorf("expected the write error from Err, got %v", s.Err())
+	}
+	s.Stop()
+}
+
+// TestErrKeepsSpinning verifies that other write errors are recorded
+// without stopping the spinner
+func TestErrKeepsSpinning(t *testing.T) {
+	withTerminal(t)
+	errWrite := errors.New("write failed")
+	s := New(CharSets[9], 5*time.Millisecond, WithWriter(failingWriter{errWrite}))
+	s.Start()
+	defer s.Stop()
+	time.Sleep(20 * time.Millisecond)
+
+	if s.Err() != errWrite {
+		t.Errorf("expected the write error from Err, got %v", s.Err())
+	}
+	if !s.Active() {
+		t.Error("expected the spinner to keep running")
+	}
+
+	for _, err := range []error{os.ErrClosed, io.ErrClosedPipe} {
+		if !isClosedWriterError(fmt.Errorf("write: %w", err)) {
+			t.Errorf("expected %v to be a closed writer error", err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// asciiTerms holds the terminals that can't be relied on to render
+// characters beyond ASCII, whatever the locale
+var asciiTerms = map[string]bool{
+	"dumb":  true,
+	"linux": true,
+	"vt100": true,
+	"vt102": true,
+	"vt220": true,
+	"ansi":  true,
+}
+
+// CharSetChoice describes how the character set of a spinner given a
+// fallback with WithFallback was chosen.
+type CharSetChoice struct {
+	UTF8     bool   // UTF8 is whether the terminal was detected to render UTF-8
+	Reason   string // Reason is the setting UTF8 was decided from, e.g. "LANG=C"
+	Fallback bool   // Fallback is whether the fallback set is used
+}
+
+// DetectUTF8 returns whether the terminal is expected to render UTF-8,
+// from the first of LC_ALL, LC_CTYPE and LANG that is set, then TERM, and
+// the setting it was decided from. On Windows only Windows Terminal is
+// expected to.
+func DetectUTF8() (bool, string) {
+	if isWindows {
+		if isWindowsTerminalOnWindows {
+			return true, "WT_SESSION set"
+		}
+		return false, "WT_SESSION not set"
+	}
+
+	var locale string
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			locale = name + "=" + v
+			break
+		}
+	}
+	if locale == "" {
+		return false, "no locale set"
+	}
+	lower := strings.ToLower(locale)
+	if !strings.Contains(lower, "utf-8") && !strings.Contains(lower, "utf8") {
+		return false, locale
+	}
+	if t := os.Getenv("TERM"); asciiTerms[t] {
+		return false, "TERM=" + t
+	}
+	return true, locale
+}
+
+// WithFallback sets the character set used instead of the one given to New
+// or UpdateCharSet when the terminal isn't detected to render UTF-8 and
+// that set isn't plain ASCII, e.g. CharSets[9]. See DetectUTF8 and
+// CharSetChoice.
+func WithFallback(cs []string) Option {
+	return func(s *Spinner) {
+		s.fallback = cs
+	}
+}
+
+// CharSetChoice returns how the character set of the spinner was chosen
+// between the one given and the fallback. It's the zero value unless a
+// fallback was given with WithFallback.
+func (s *Spinner) CharSetChoice() CharSetChoice {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.choice
+}
+
+// chooseCharSet returns the given character set, or the fallback if the
+// given set can't be rendered, and records the choice. Caller must
+// already hold s.lock.
+func (s *Spinner) chooseCharSet(cs []string) []string {
+	if s.fallback == nil {
+		return cs
+	}
+	utf8, reason := DetectUTF8()
+	s.choice = CharSetChoice{UTF8: utf8, Reason: reason}
+	if utf8 || isASCII(cs) {
+		return cs
+	}
+	s.choice.Fallback = true
+	return s.fallback
+}
+
+// isASCII returns whether the frames only contain ASCII characters.
+func isASCII(frames []string) bool {
+	for _, f := range frames {
+		for _, r := range f {
+			if r > unicode.MaxASCII {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setLocale sets the locale and terminal environment variables until the
+// test completes
+func setLocale(t *testing.T, lcAll, lcCtype, lang, term string) {
+	t.Setenv("LC_ALL", lcAll)
+	t.Setenv("LC_CTYPE", lcCtype)
+	t.Setenv("LANG", lang)
+	t.Setenv("TERM", term)
+}
+
+// TestDetectUTF8 verifies that UTF-8 is detected from the locale and the
+// terminal
+func TestDetectUTF8(t *testing.T) {
+	if isWindows {
+		t.Skip("detection doesn't use the locale on Windows")
+	}
+	tests := []struct {
+		lcAll, lcCtype, lang, term string
+		want                       bool
+		reason                     string
+	}{
+		{"", "", "en_US.UTF-8", "xterm-256color", true, "LANG=en_US.UTF-8"},
+		{"", "C.utf8", "C", "xterm", true, "LC_CTYPE=C.utf8"},
+		{"C", "", "en_US.UTF-8", "xterm", false, "LC_ALL=C"},
+		{"", "", "C", "xterm", false, "LANG=C"},
+		{"", "", "", "xterm", false, "no locale set"},
+		{"", "", "en_US.UTF-8", "linux", false, "TERM=linux"},
+	}
+
+	for _, tt := range tests {
+		setLocale(t, tt.lcAll, tt.lcCtype, tt.lang, tt.term)
+		got, reason := DetectUTF8()
+		if got != tt.want || reason != tt.reason {
+			t.Errorf("got %t, %q, want %t, %q", got, reason, tt.want, tt.reason)
+		}
+	}
+}
+
+// TestWithFallback verifies that the fallback replaces character sets
+// that can't be rendered
+func TestWithFallback(t *testing.T) {
+	if isWindows {
+		t.Skip("detection doesn't use the locale on Windows")
+	}
+	tests := []struct {
+		name     string
+		lang     string
+		cs       []string
+		want     []string
+		fallback bool
+	}{
+		{"UTF-8", "en_US.UTF-8", CharSets[14], CharSets[14], false},
+		{"ASCII terminal", "C", CharSets[14], CharSets[9], true},
+		{"ASCII set", "C", CharSets[26], CharSets[26], false},
+	}
+
+	for _, tt := range tests {
+		setLocale(t, "", "", tt.lang, "xterm")
+		s := New(tt.cs, time.Second, WithFallback(CharSets[9]))
+		if !reflect.DeepEqual(s.chars, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, s.chars, tt.want)
+		}
+		if got := s.CharSetChoice(); got.Fallback != tt.fallback || got.Reason != "LANG="+tt.lang {
+			t.Errorf("%s: got choice %+v", tt.name, got)
+		}
+	}
+
+	setLocale(t, "", "", "C", "xterm")
+	s := New(CharSets[9], time.Second, WithFallback(CharSets[26]))
+	s.UpdateCharSet(CharSets[14])
+	if !reflect.DeepEqual(s.chars, CharSets[26]) || !s.CharSetChoice().Fallback {
+		t.Errorf("UpdateCharSet: got %q, want the fallback", s.chars)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import "strings"
+
+// The functions below compose character sets into new animations. They
+// return new slices and never modify the frames they are given, so they
+// may be used on the sets in CharSets.
+
+// Reversed returns the frames in reverse order.
+func Reversed(frames []string) []string {
+	out := make([]string, len(frames))
+	for i, f := range frames {
+		out[len(frames)-1-i] = f
+	}
+	return out
+}
+
+// PingPong returns the frames followed by the same frames backwards,
+// without repeating the first and last frames, so the animation plays
+// back and forth.
+func PingPong(frames []string) []string {
+	out := append([]string{}, frames...)
+	for i := len(frames) - 2; i > 0; i-- {
+		out = append(out, frames[i])
+	}
+	return out
+}
+
+// Concat returns the frames of the given sets one after the other.
+func Concat(sets ...[]string) []string {
+	var out []string
+	for _, frames := range sets {
+		out = append(out, frames...)
+	}
+	return out
+}
+
+// Repeat returns the frames repeated n times.
+func Repeat(frames []string, n int) []string {
+	var out []string
+	for i := 0; i < n; i++ {
+		out = append(out, frames...)
+	}
+	return out
+}
+
+// Interleave returns the first frame of each set, then the second frame of
+// each set, and so on. Sets that run out of frames are skipped.
+func Interleave(sets ...[]string) []string {
+	var out []string
+	for i := 0; ; i++ {
+		n := len(out)
+		for _, frames := range sets {
+			if i < len(frames) {
+				out = append(out, frames[i])
+			}
+		}
+		if len(out) == n {
+			return out
+		}
+	}
+}
+
+// Pad returns the frames padded with spaces on the right to the display
+// width of the widest frame, so the text after the spinner doesn't move.
+func Pad(frames []string) []string {
+	width := 0
+	for _, f := range frames {
+		if w := displayWidth(f); w > width {
+			width = w
+		}
+	}
+	out := make([]string, len(frames))
+	for i, f := range frames {
+		out[i] = f + strings.Repeat(" ", width-displayWidth(f))
+	}
+	return out
+}
+
+// mirrored holds the characters swapped by Mirror
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'/': '\\', '\\': '/',
+	'←': '→', '→': '←',
+	'▏': '▕', '▕': '▏',
+	'▌': '▐', '▐': '▌',
+}
+
+// Mirror returns the frames flipped horizontally: the characters of each
+// frame are reversed and those with a mirror image, like brackets and
+// arrows, are swapped for it. An animation moving right moves left.
+func Mirror(frames []string) []string {
+	out := make([]string, len(frames))
+	for i, f := range frames {
+		runes := []rune(f)
+		for j, k := 0, len(runes)-1; j <= k; j, k = j+1, k-1 {
+			runes[j], runes[k] = mirror(runes[k]), mirror(runes[j])
+		}
+		out[i] = string(runes)
+	}
+	return out
+}
+
+// mirror returns the mirror image of r, or r if it has none.
+func mirror(r rune) rune {
+	if m, ok := mirrored[r]; ok {
+		return m
+	}
+	return r
+}
+
+// WithFrames applies the given transformations, e.g. PingPong or Pad, to
+// the character set of the spinner in order. The timing of the frames,
+// from CharSetTimings or WithTiming, is dropped as it can't be matched to
+// the new frames; give WithTiming after WithFrames to time them.
+func WithFrames(transforms ...func([]string) []string) Option {
+	return func(s *Spinner) {
+		for _, t := range transforms {
+			s.chars = t(s.chars)
+		}
+		if len(transforms) > 0 {
+			s.timing = nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestFrameCombinators verifies the frames returned by the combinators
+func TestFrameCombinators(t *testing.T) {
+	abc := []string{"a", "b", "c"}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Reversed", Reversed(abc), []string{"c", "b", "a"}},
+		{"PingPong", PingPong(abc), []string{"a", "b", "c", "b"}},
+		{"PingPong two frames", PingPong([]string{"a", "b"}), []string{"a", "b"}},
+		{"Concat", Concat(abc, []string{"d"}), []string{"a", "b", "c", "d"}},
+		{"Repeat", Repeat([]string{"a", "b"}, 2), []string{"a", "b", "a", "b"}},
+		{"Repeat zero times", Repeat(abc, 0), nil},
+		{"Interleave", Interleave(abc, []string{"1", "2"}), []string{"a", "1", "b", "2", "c"}},
+		{"Pad", Pad([]string{"a", "世", "abc"}), []string{"a  ", "世 ", "abc"}},
+		{"Mirror", Mirror([]string{"(●  )", "=>", "▐x"}), []string{"(  ●)", "<=", "x▌"}},
+	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if !reflect.DeepEqual(abc, []string{"a", "b", "c"}) {
+		t.Errorf("combinators modified their input: %q", abc)
+	}
+}
+
+// TestWithFrames verifies that the transformations are applied in order
+func TestWithFrames(t *testing.T) {
+	s := New([]string{"a", "b", "c"}, time.Second, WithFrames(PingPong, Reversed))
+	if want := []string{"b", "c", "b", "a"}; !reflect.DeepEqual(s.chars, want) {
+		t.Errorf("got %q, want %q", s.chars, want)
+	}
+}
+
+// TestWithFramesDropsTiming verifies that the timing of the original
+// frames isn't applied to the transformed ones
+func TestWithFramesDropsTiming(t *testing.T) {
+	s := New(CharSets[12], time.Second, WithFrames(Reversed))
+	if s.timing != nil {
+		t.Errorf("got timing %v for the transformed frames", s.timing)
+	}
+
+	timing := Hold(4, 3, 0)
+	s = New([]string{"a", "b"}, time.Second, WithFrames(func(f []string) []string { return Repeat(f, 2) }), WithTiming(timing))
+	if !reflect.DeepEqual(s.timing, timing) {
+		t.Errorf("got timing %v, want %v given after WithFrames", s.timing, timing)
+	}
+}
+
+// TestReverseKeepsCharSets verifies that Reverse doesn't modify the
+// character set given to the spinner
+func TestReverseKeepsCharSets(t *testing.T) {
+	want := append([]string{}, CharSets[9]...)
+	s := New(CharSets[9], time.Second)
+	s.Reverse()
+
+	if !reflect.DeepEqual(CharSets[9], want) {
+		t.Errorf("CharSets[9] was modified: got %q, want %q", CharSets[9], want)
+	}
+	if !reflect.DeepEqual(s.chars, Reversed(want)) {
+		t.Errorf("got %q, want %q", s.chars, Reversed(want))
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import "strings"
+
+// waveLevels holds the block elements drawn by GenerateWave, from lowest
+// to highest
+var waveLevels = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// GenerateBar will generate the frames of a bar of the given width, in
+// cells, filling up from empty to full like CharSets[36]. Each frame is
+// width + 2 cells wide, brackets included. fill, empty and head must be
+// one cell wide, otherwise nil is returned.
+func GenerateBar(width int, fill, empty, head string) []string {
+	if width < 1 || displayWidth(fill) != 1 || displayWidth(empty) != 1 || displayWidth(head) != 1 {
+		return nil
+	}
+	frames := make([]string, width+1)
+	for i := range frames {
+		frames[i] = barFrame(width, i, fill, empty, head)
+	}
+	return frames
+}
+
+// barFrame returns a bar of the given width with filled cells completed,
+// followed by head unless the bar is empty or full.
+func barFrame(width, filled int, fill, empty, head string) string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	sb.WriteString(strings.Repeat(fill, filled))
+	if filled < width {
+		if filled > 0 {
+			sb.WriteString(head)
+			filled++
+		}
+		sb.WriteString(strings.Repeat(empty, width-filled))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// GenerateScanner will generate the frames of the given glyph moving back
+// and forth over a track of the given width, in cells, like CharSets[79].
+// track must be one cell wide and glyph no wider than the track, otherwise
+// nil is returned.
+func GenerateScanner(width int, glyph, track string) []string {
+	positions := width - displayWidth(glyph)
+	if positions < 0 || displayWidth(track) != 1 {
+		return nil
+	}
+	frames := make([]string, positions+1)
+	for i := range frames {
+		frames[i] = strings.Repeat(track, i) + glyph + strings.Repeat(track, positions-i)
+	}
+	return PingPong(frames)
+}
+
+// GenerateBounce will generate the frames of the given glyph bouncing
+// between two walls the given width, in cells, apart, like CharSets[52].
+// glyph must be no wider than width, otherwise nil is returned.
+func GenerateBounce(width int, glyph string) []string {
+	frames := GenerateScanner(width, glyph, " ")
+	for i, f := range frames {
+		frames[i] = "(" + f + ")"
+	}
+	return frames
+}
+
+// GenerateWave will generate the frames of a wave of the given width, in
+// cells, drawn with the block elements of CharSets[1] and moving left.
+func GenerateWave(width int) []string {
+	if width < 1 {
+		return nil
+	}
+	levels := PingPong(waveLevels)
+	frames := make([]string, len(levels))
+	for i := range frames {
+		var sb strings.Builder
+		for x := 0; x < width; x++ {
+			sb.WriteString(levels[(x+i)%len(levels)])
+		}
+		frames[i] = sb.String()
+	}
+	return frames
+}
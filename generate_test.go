@@ -0,0 +1,76 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestGenerators verifies the frames of the generators and that they all
+// have the expected display width
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []string
+		width  int
+		want   []string
+	}{
+		{"GenerateBar", GenerateBar(3, "=", " ", ">"), 5, []string{"[   ]", "[=> ]", "[==>]", "[===]"}},
+		{"GenerateScanner", GenerateScanner(3, "*", "-"), 3, []string{"*--", "-*-", "--*", "-*-"}},
+		{"GenerateScanner wide glyph", GenerateScanner(4, "世", "-"), 4, []string{"世--", "-世-", "--世", "-世-"}},
+		{"GenerateBounce", GenerateBounce(3, "●"), 5, []string{"(●  )", "( ● )", "(  ●)", "( ● )"}},
+		{"GenerateWave", GenerateWave(3), 3, nil},
+		{"GenerateWave long", GenerateWave(40), 40, nil},
+		{"GenerateBar long", GenerateBar(40, "█", "░", "▓"), 42, nil},
+	}
+
+	for _, tt := range tests {
+		if len(tt.frames) == 0 {
+			t.Errorf("%s: no frames", tt.name)
+		}
+		if tt.want != nil && !reflect.DeepEqual(tt.frames, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.frames, tt.want)
+		}
+		for _, f := range tt.frames {
+			if w := displayWidth(f); w != tt.width {
+				t.Errorf("%s: frame %q is %d cells wide, want %d", tt.name, f, w, tt.width)
+			}
+		}
+	}
+}
+
+// TestGeneratorsRejectInvalidWidths verifies that the generators return no
+// frames when the frames couldn't have a constant width
+func TestGeneratorsRejectInvalidWidths(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []string
+	}{
+		{"GenerateBar zero width", GenerateBar(0, "=", " ", ">")},
+		{"GenerateBar wide fill", GenerateBar(5, "世", " ", ">")},
+		{"GenerateBar empty head", GenerateBar(5, "=", " ", "")},
+		{"GenerateScanner wide track", GenerateScanner(5, "*", "--")},
+		{"GenerateScanner glyph too wide", GenerateScanner(1, "世", "-")},
+		{"GenerateBounce glyph too wide", GenerateBounce(1, "世")},
+		{"GenerateWave zero width", GenerateWave(0)},
+	}
+
+	for _, tt := range tests {
+		if tt.frames != nil {
+			t.Errorf("%s: got %q, want no frames", tt.name, tt.frames)
+		}
+	}
+}
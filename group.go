@@ -0,0 +1,186 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TaskGroup runs tasks concurrently, similar to errgroup.Group, showing a
+// line per task with its spinner, name and status, followed by a summary.
+// The lines are drawn with the character set, color, delay and writer of
+// the spinner given to NewTaskGroup.
+type TaskGroup struct {
+	mu    sync.Mutex
+	s     *Spinner
+	block *block
+	wg    sync.WaitGroup
+	sem   chan struct{}
+	tasks []*task
+	err   error
+}
+
+// task holds the state of a task in a TaskGroup.
+type task struct {
+	name      string
+	running   bool
+	done      bool
+	err       error
+	start     time.Time
+	elapsed   time.Duration
+	started   bool // started is set once the start is announced
+	announced bool // announced is set once the outcome is announced
+}
+
+// NewTaskGroup provides a pointer to an instance of TaskGroup drawing
+// its lines with the given spinner. The spinner itself must not be
+// started.
+func NewTaskGroup(s *Spinner) *TaskGroup {
+	g := &TaskGroup{s: s}
+	g.block = newBlock(s, g.render, g.announce)
+	return g
+}
+
+// SetLimit limits the number of tasks running at once to n. Zero or a
+// negative value removes the limit. It must not be called while tasks are
+// running.
+func (g *TaskGroup) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs fn in a new goroutine, showing it under the given name. The
+// first call starts drawing the lines. Tasks beyond the limit set with
+// SetLimit are shown as waiting until another task completes.
+func (g *TaskGroup) Go(name string, fn func() error) {
+	t := &task{name: name}
+	g.mu.Lock()
+	g.tasks = append(g.tasks, t)
+	g.mu.Unlock()
+	g.block.start()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			g.sem <- struct{}{}
+			defer func() { <-g.sem }()
+		}
+
+		g.mu.Lock()
+		t.running, t.start = true, time.Now()
+		g.mu.Unlock()
+
+		err := fn()
+
+		g.mu.Lock()
+		t.running, t.done, t.err = false, true, err
+		t.elapsed = time.Since(t.start)
+		if err != nil && g.err == nil {
+			g.err = err
+		}
+		g.mu.Unlock()
+	}()
+}
+
+// Wait waits for all tasks to complete, leaves their final status lines
+// and the summary on screen and returns the first error returned by a
+// task, if any. In accessible mode only the summary is left, following
+// the announced outcome of each task.
+func (g *TaskGroup) Wait() error {
+	g.wg.Wait()
+	g.s.mu.Lock()
+	final := g.render("")
+	if g.s.accessible {
+		g.mu.Lock()
+		final = g.summary()
+		g.mu.Unlock()
+	}
+	g.s.mu.Unlock()
+	g.block.finish(final + "\n")
+	return g.err
+}
+
+// render returns the lines of the tasks and the summary, using frame for
+// running tasks. Caller must already hold g.s.lock.
+func (g *TaskGroup) render(frame string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var b strings.Builder
+	for _, t := range g.tasks {
+		switch {
+		case t.done && t.err != nil:
+			b.WriteString(statusLine(Failure, t.name, t.err, t.elapsed))
+		case t.done:
+			b.WriteString(statusLine(Success, t.name, nil, t.elapsed))
+		case t.running:
+			fmt.Fprintf(&b, "%s %s\n", frame, t.name)
+		default:
+			fmt.Fprintf(&b, "%s %s (waiting)\n", strings.Repeat(" ", displayWidth(frame)), t.name)
+		}
+	}
+	b.WriteString(g.summary())
+	return b.String()
+}
+
+// summary returns the number of completed and failed tasks, e.g.
+// "3/4 done, 1 failed". Caller must already hold g.mu.
+func (g *TaskGroup) summary() string {
+	done, failed := 0, 0
+	for _, t := range g.tasks {
+		if t.done {
+			done++
+		}
+		if t.err != nil {
+			failed++
+		}
+	}
+	text := fmt.Sprintf("%d/%d done", done, len(g.tasks))
+	if failed > 0 {
+		text += fmt.Sprintf(", %d failed", failed)
+	}
+	return text
+}
+
+// announce announces the tasks that started or completed since the
+// previous call, in accessible mode. Tasks completing before their start
+// was announced only have their outcome announced. Caller must already
+// hold g.s.lock.
+func (g *TaskGroup) announce() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, t := range g.tasks {
+		switch {
+		case t.done && !t.announced:
+			st := Success
+			if t.err != nil {
+				st = Failure
+			}
+			g.s.announceStatus(st, t.name, t.err, t.elapsed)
+			t.started, t.announced = true, true
+		case t.running && !t.started:
+			g.s.announce("Started", t.name)
+			t.started = true
+		}
+	}
+}
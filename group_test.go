@@ -0,0 +1,177 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestTaskGroup verifies the final status lines, summary and error
+func TestTaskGroup(t *testing.T) {
+	s, out := withOutput(CharSets[9], 10*time.Millisecond)
+	g := NewTaskGroup(s)
+
+	errPull := errors.New("manifest unknown")
+	g.Go("pull nginx", func() error { return nil })
+	g.Go("pull redis", func() error { return errPull })
+	g.Go("pull postgres", func() error { return nil })
+
+	if err := g.Wait(); err != errPull {
+		t.Errorf("expected the task error, got %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected a line per task and a summary, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[0], Success.String()+" pull nginx (") {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], Failure.String()+" pull redis: manifest unknown (") {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+	if lines[3] != "3/3 done, 1 failed" {
+		t.Errorf("unexpected summary %q", lines[3])
+	}
+}
+
+// TestTaskGroupLimit verifies that no more tasks than the limit run at once
+func TestTaskGroupLimit(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput(CharSets[9], 5*time.Millisecond)
+	g := NewTaskGroup(s)
+	g.SetLimit(2)
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	for i := 0; i < 6; i++ {
+		g.Go("task", func() error {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if peak != 2 {
+		t.Errorf("expected 2 tasks running at once, got %d", peak)
+	}
+	result := out.String()
+	if !strings.Contains(result, "task (waiting)") {
+		t.Errorf("expected waiting tasks to be shown, got %q", result)
+	}
+	if !strings.HasSuffix(result, "6/6 done\n") {
+		t.Errorf("expected the summary at the end, got %q", result)
+	}
+	if !strings.Contains(result, "\033[F\033[K") {
+		t.Errorf("expected previous lines to be erased, got %q", result)
+	}
+}
+
+// TestTaskGroupReuse verifies that tasks can be added again after Wait
+func TestTaskGroupReuse(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput(CharSets[9], 5*time.Millisecond)
+	g := NewTaskGroup(s)
+
+	for i := 0; i < 2; i++ {
+		g.Go("task", func() error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+		if err := g.Wait(); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if !strings.HasSuffix(out.String(), "2/2 done\n") {
+		t.Errorf("expected the summary of both tasks, got %q", out.String())
+	}
+}
+
+// TestTaskGroupZeroLimit verifies that a limit of zero runs every task
+func TestTaskGroupZeroLimit(t *testing.T) {
+	s, _ := withOutput(CharSets[9], 5*time.Millisecond)
+	g := NewTaskGroup(s)
+	g.SetLimit(0)
+
+	for i := 0; i < 3; i++ {
+		g.Go("task", func() error { return nil })
+	}
+	done := make(chan error)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the tasks to run without a limit")
+	}
+}
+
+// TestTaskGroupClosedWriter verifies that the lines stop being drawn once
+// the reader of the pipe went away
+func TestTaskGroupClosedWriter(t *testing.T) {
+	withTerminal(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	r.Close()
+
+	s := New(CharSets[9], 5*time.Millisecond, WithWriterFile(w))
+	var mu sync.Mutex
+	writeErrors := 0
+	s.OnEvent(func(e Event) {
+		if e.Type == EventWriteError {
+			mu.Lock()
+			writeErrors++
+			mu.Unlock()
+		}
+	})
+	g := NewTaskGroup(s)
+	g.Go("task", func() error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if writeErrors != 1 {
+		t.Errorf("expected drawing to stop at the first write error, got %d", writeErrors)
+	}
+	if !errors.Is(s.Err(), syscall.EPIPE) {
+		t.Errorf("expected a broken pipe from Err, got %v", s.Err())
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package model provides a spinner for programs built around an
+// Elm-style update and view loop, like Bubble Tea. The Model never writes
+// to a terminal nor starts a goroutine: it advances a frame for every
+// TickMsg given to Update and View returns the current frame.
+//
+//	m := model.New(spinner.CharSets[9], 100*time.Millisecond, spinner.WithColor("cyan"))
+//	cmd := m.Init()
+//	// in the event loop, run cmd and give the resulting message to Update
+//	m, cmd = m.Update(msg)
+//	fmt.Print(m.View())
+//
+// Msg and Cmd have the same shape as those of Bubble Tea, which a Cmd
+// returned by the Model can be converted to with
+// func() tea.Msg { return cmd() }.
+package model
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/briandowns/spinner"
+)
+
+// Msg is a message handled by Update.
+type Msg interface{}
+
+// Cmd is a function run by the event loop, returning a message to give to
+// Update. A nil Cmd does nothing.
+type Cmd func() Msg
+
+// TickMsg advances the Model with the same ID to its next frame.
+type TickMsg struct {
+	Time time.Time // Time the tick was sent
+	ID   int       // ID of the Model the tick is for
+	tag  int       // tag drops ticks sent before the last one
+}
+
+// lastID holds the ID of the last Model created
+var lastID int64
+
+// Model is a spinner animated by the TickMsg given to Update. It's a
+// value: Update returns the changed Model.
+type Model struct {
+	s     *spinner.Spinner
+	id    int
+	tag   int
+	frame int
+}
+
+// New returns a Model showing the given character set at the given
+// delay. The options of the spinner package, like WithColor and
+// WithSuffix, set the color and text around the frames.
+func New(cs []string, d time.Duration, options ...spinner.Option) Model {
+	return Model{
+		s:  spinner.New(cs, d, options...),
+		id: int(atomic.AddInt64(&lastID, 1)),
+	}
+}
+
+// ID returns the ID identifying the ticks of the Model.
+func (m Model) ID() int {
+	return m.id
+}
+
+// Init returns the Cmd starting the animation.
+func (m Model) Init() Cmd {
+	return m.Tick
+}
+
+// Tick returns a TickMsg advancing the Model to its next frame.
+func (m Model) Tick() Msg {
+	return TickMsg{Time: time.Now(), ID: m.id, tag: m.tag}
+}
+
+// Update advances the Model to its next frame when given its TickMsg and
+// returns the Cmd sending the following tick once the frame is due.
+// Other messages and ticks, including ticks of other Models and those
+// superseded by a later tick, are ignored.
+func (m Model) Update(msg Msg) (Model, Cmd) {
+	t, ok := msg.(TickMsg)
+	if !ok || t.ID != m.id || t.tag != m.tag {
+		return m, nil
+	}
+	m.frame++
+	m.tag++
+	return m, m.tick()
+}
+
+// tick returns the Cmd sending the next tick once the current frame has
+// been shown for its duration.
+func (m Model) tick() Cmd {
+	delay := m.s.Render(m.frame).Deadline - m.s.Render(m.frame-1).Deadline
+	id, tag := m.id, m.tag
+	return func() Msg {
+		t := <-time.After(delay)
+		return TickMsg{Time: t, ID: id, tag: tag}
+	}
+}
+
+// View returns the current frame along with the prefix and suffix of the
+// spinner.
+func (m Model) View() string {
+	return m.s.Render(m.frame).Text
+}
+
+// Frame returns the current frame as returned by spinner.Render.
+func (m Model) Frame() spinner.Frame {
+	return m.s.Render(m.frame)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/briandowns/spinner"
+)
+
+// TestUpdate verifies that every tick of the model advances it to its next
+// frame
+func TestUpdate(t *testing.T) {
+	m := New([]string{"a", "b", "c"}, time.Millisecond, spinner.WithSuffix(" loading"))
+	if got, want := m.Frame().Plain, "a loading"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	msg := m.Init()()
+	for _, want := range []string{"b loading", "c loading", "a loading"} {
+		var cmd Cmd
+		m, cmd = m.Update(msg)
+		if m.Frame().Plain != want {
+			t.Errorf("got %q, want %q", m.Frame().Plain, want)
+		}
+		if cmd == nil {
+			t.Fatal("no command sending the next tick")
+		}
+		msg = cmd()
+	}
+}
+
+// TestUpdateIgnoresOtherMessages verifies that only the last tick of the
+// model advances it
+func TestUpdateIgnoresOtherMessages(t *testing.T) {
+	m := New([]string{"a", "b"}, time.Millisecond)
+	other := New([]string{"a", "b"}, time.Millisecond)
+	tick := m.Tick()
+	next, _ := m.Update(tick)
+
+	tests := []struct {
+		name string
+		msg  Msg
+	}{
+		{"other message", "key"},
+		{"other model", other.Tick()},
+		{"superseded tick", tick},
+	}
+
+	for _, tt := range tests {
+		got, cmd := next.Update(tt.msg)
+		if got.View() != next.View() {
+			t.Errorf("%s: got view %q, want %q", tt.name, got.View(), next.View())
+		}
+		if got.Frame().Index != next.Frame().Index || cmd != nil {
+			t.Errorf("%s: model advanced to frame %d", tt.name, got.Frame().Index)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// OutputTracker is an io.Writer that shows the most recent line written
+// to it as the suffix of a spinner, e.g. the output of a subprocess. The
+// full output is copied to the tee writer when one is given.
+type OutputTracker struct {
+	mu      sync.Mutex
+	s       *Spinner
+	tee     io.Writer
+	suffix  string // suffix of the spinner before tracking started
+	partial []byte // output written after the last line break
+}
+
+// maxPartialLine is the most bytes of a line kept until its line break,
+// far more than fits the width of a terminal. The rest of a longer line is
+// dropped so output without line breaks doesn't grow the tracker.
+const maxPartialLine = 4096
+
+// NewOutputTracker returns an OutputTracker updating the suffix of the
+// given spinner. The output is also written to tee when it isn't nil.
+func NewOutputTracker(s *Spinner, tee io.Writer) *OutputTracker {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &OutputTracker{
+		s:      s,
+		tee:    tee,
+		suffix: s.Suffix,
+	}
+}
+
+// Write shows the last non-empty line in p as the suffix of the spinner.
+// Carriage returns are treated as line breaks so progress output that
+// rewrites its line is followed as well.
+func (t *OutputTracker) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := len(p)
+	if t.tee != nil {
+		if _, err := t.tee.Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	// only the new bytes are scanned, the partial line held so far is
+	// completed by the first line break
+	var line string
+	if i := bytes.IndexAny(p, "\r\n"); i >= 0 {
+		t.appendPartial(p[:i])
+		line = trackedLine(t.partial)
+		t.partial = t.partial[:0]
+		p = p[i+1:]
+		if i := bytes.LastIndexAny(p, "\r\n"); i >= 0 {
+			if l := lastLine(p[:i]); l != "" {
+				line = l
+			}
+			p = p[i+1:]
+		}
+	}
+	t.appendPartial(p)
+	if l := trackedLine(t.partial); l != "" {
+		line = l
+	}
+	if line == "" {
+		return n, nil
+	}
+
+	t.s.mu.Lock()
+	t.s.Suffix = t.suffix + " " + line
+	if width := t.s.terminalWidth(); width > 0 {
+		available := width - displayWidth(t.s.Prefix) - t.s.frameWidth() - 1
+		t.s.Suffix = truncateWidth(t.s.Suffix, available)
+	}
+	t.s.mu.Unlock()
+	return n, nil
+}
+
+// appendPartial appends b to the line held until its line break, keeping
+// at most maxPartialLine bytes of it. Caller must already hold t.mu.
+func (t *OutputTracker) appendPartial(b []byte) {
+	n := maxPartialLine - len(t.partial)
+	if len(b) <= n {
+		t.partial = append(t.partial, b...)
+		return
+	}
+	if n <= 0 {
+		return
+	}
+	// drop the rune cut in half at the end
+	t.partial = bytes.ToValidUTF8(append(t.partial, b[:n]...), nil)
+}
+
+// trackedLine returns the line shown in the suffix for b, without escape
+// sequences, control characters and surrounding spaces, so the colors of
+// the output don't leak into the rest of the spinner line.
+func trackedLine(b []byte) string {
+	return strings.TrimSpace(stripControl(string(b)))
+}
+
+// lastLine returns the last non-empty line in b as shown by trackedLine.
+func lastLine(b []byte) string {
+	for {
+		i := bytes.LastIndexAny(b, "\r\n")
+		if line := trackedLine(b[i+1:]); line != "" || i < 0 {
+			return line
+		}
+		b = b[:i]
+	}
+}
+
+// Restore sets the suffix of the spinner back to its value before
+// tracking started.
+func (t *OutputTracker) Restore() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.s.mu.Lock()
+	t.s.Suffix = t.suffix
+	t.s.mu.Unlock()
+}
+
+// RunCmd starts the spinner and runs the given command, showing the last
+// line of its output as the suffix. The output is also written to tee when
+// it isn't nil. The spinner is stopped when the command completes and, if
+// the command failed, the error is reported in place of FinalMSG, which is
+// restored afterwards.
+func (s *Spinner) RunCmd(cmd *exec.Cmd, tee io.Writer) error {
+	t := NewOutputTracker(s, tee)
+	cmd.Stdout = t
+	cmd.Stderr = t
+
+	s.Start()
+	err := cmd.Run()
+	t.Restore()
+	s.finish(strings.Join(cmd.Args, " "), err)
+	return err
+}
+
+// Follow starts the spinner and reads the given readers until they are
+// exhausted, e.g. the stdout and stderr pipes of a subprocess, showing the
+// last line read as the suffix. The output is also written to tee when it
+// isn't nil. The spinner is stopped when all readers are exhausted and, if
+// reading failed, the error is reported in place of FinalMSG, which is
+// restored afterwards.
+func (s *Spinner) Follow(tee io.Writer, readers ...io.Reader) error {
+	t := NewOutputTracker(s, tee)
+
+	s.Start()
+	errs := make(chan error, len(readers))
+	for _, r := range readers {
+		go func(r io.Reader) {
+			_, err := io.Copy(t, r)
+			errs <- err
+		}(r)
+	}
+	var err error
+	for range readers {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+	t.Restore()
+	s.finish("", err)
+	return err
+}
+
+// finish stops the spinner, reporting the given error in place of
+// FinalMSG like Run does, so a later Stop doesn't report it again.
+func (s *Spinner) finish(desc string, err error) {
+	if err == nil {
+		s.Stop()
+		return
+	}
+
+	s.mu.Lock()
+	finalMsg := s.FinalMSG
+	if desc != "" {
+		s.FinalMSG = fmt.Sprintf("%s: %v\n", desc, err)
+	} else {
+		s.FinalMSG = fmt.Sprintf("%v\n", err)
+	}
+	s.mu.Unlock()
+	s.Stop()
+
+	s.mu.Lock()
+	s.FinalMSG = finalMsg
+	s.mu.Unlock()
+}
+
+// terminalWidth returns the width of the terminal the spinner is written
+// to, or 0 if it isn't known.
+func (s *Spinner) terminalWidth() int {
+	if s.WriterFile == nil {
+		return 0
+	}
+	width, _, err := term.GetSize(int(s.WriterFile.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// frameWidth returns the width of the widest frame in the character set.
+// Caller must already hold s.lock.
+func (s *Spinner) frameWidth() int {
+	width := 0
+	for _, c := range s.chars {
+		if w := displayWidth(c); w > width {
+			width = w
+		}
+	}
+	return width
+}
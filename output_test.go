@@ -0,0 +1,191 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// TestOutputTracker verifies that the suffix follows the last line written
+func TestOutputTracker(t *testing.T) {
+	s, _ := withOutput(CharSets[9], 100*time.Millisecond)
+	s.Suffix = " Building"
+	var tee bytes.Buffer
+	tracker := NewOutputTracker(s, &tee)
+
+	tests := []struct {
+		description string
+		written     string
+		expected    string
+	}{
+		{"FirstLine", "compiling a\n", " Building compiling a"},
+		{"SeveralLines", "compiling b\ncompiling c\n\n", " Building compiling c"},
+		{"PartialLine", "link", " Building link"},
+		{"CompletedLine", "ing\n", " Building linking"},
+		{"CarriageReturn", "10%\r20%\r", " Building 20%"},
+		{"Blank", "   \n", " Building 20%"},
+		{"Colors", "\033[31merror:\tfailed\033[0m\n", " Building error: failed"},
+		{"OnlyEscapes", "\033[0m\n", " Building error: failed"},
+	}
+
+	for _, test := range tests {
+		tracker.Write([]byte(test.written))
+		if s.Suffix != test.expected {
+			t.Errorf("%s: expected suffix %q, got %q", test.description, test.expected, s.Suffix)
+		}
+	}
+
+	tracker.Restore()
+	if s.Suffix != " Building" {
+		t.Errorf("expected suffix to be restored, got %q", s.Suffix)
+	}
+	if !strings.HasPrefix(tee.String(), "compiling a\ncompiling b\n") {
+		t.Errorf("expected full output to be copied, got %q", tee.String())
+	}
+}
+
+// TestOutputTrackerLongLine verifies that a line written without line
+// breaks is capped while it's held
+func TestOutputTrackerLongLine(t *testing.T) {
+	s, _ := withOutput(CharSets[9], 100*time.Millisecond)
+	tracker := NewOutputTracker(s, nil)
+
+	// the odd byte puts the cap in the middle of a rune
+	tracker.Write([]byte("a"))
+	chunk := []byte(strings.Repeat("é", 100))
+	for i := 0; i < 100; i++ {
+		tracker.Write(chunk)
+	}
+	if len(tracker.partial) > maxPartialLine {
+		t.Errorf("expected at most %d bytes held, got %d", maxPartialLine, len(tracker.partial))
+	}
+	if !utf8.Valid(tracker.partial) {
+		t.Error("expected the held line to end with a whole rune")
+	}
+
+	tracker.Write([]byte("\ndone\n"))
+	if s.Suffix != " done" {
+		t.Errorf("expected the next line to be shown, got %q", s.Suffix)
+	}
+	if len(tracker.partial) != 0 {
+		t.Errorf("expected no partial line, got %q", tracker.partial)
+	}
+}
+
+// TestTruncateWidth verifies that lines are cut to the given width
+// without counting or cutting escape sequences
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s        string
+		n        int
+		expected string
+	}{
+		{"hello", 5, "hello"},
+		{"hello world", 5, "hell…"},
+		{"世界世界", 5, "世界…"},
+		{"\033[31mhello world\033[0m", 5, "\033[31mhell…\033[0m"},
+		{"\033[31mhello\033[0m", 5, "\033[31mhello\033[0m"},
+		{"hello", 0, ""},
+	}
+
+	for _, test := range tests {
+		if got := truncateWidth(test.s, test.n); got != test.expected {
+			t.Errorf("truncateWidth(%q, %d): expected %q, got %q", test.s, test.n, test.expected, got)
+		}
+	}
+}
+
+// TestRunCmd verifies that a failed command is reported in place of
+// FinalMSG, which is kept for later runs
+func TestRunCmd(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	withTerminal(t)
+	s, out := withOutput(CharSets[9], 100*time.Millisecond)
+	s.Suffix = " Testing"
+	var tee bytes.Buffer
+
+	err := s.RunCmd(exec.Command("sh", "-c", "echo one; echo two >&2; exit 3"), &tee)
+	if err == nil {
+		t.Fatal("expected an error from a failing command")
+	}
+	if tee.String() != "one\ntwo\n" {
+		t.Errorf("expected output to be copied, got %q", tee.String())
+	}
+	if s.Suffix != " Testing" {
+		t.Errorf("expected suffix to be restored, got %q", s.Suffix)
+	}
+	if !strings.HasSuffix(out.String(), "sh -c echo one; echo two >&2; exit 3: exit status 3\n") {
+		t.Errorf("expected the error to be reported, got %q", out.String())
+	}
+	if s.FinalMSG != "" {
+		t.Errorf("expected final message to be restored, got %q", s.FinalMSG)
+	}
+
+	s.FinalMSG = "done"
+	if err := s.RunCmd(exec.Command("sh", "-c", "exit 0"), nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if s.FinalMSG != "done" || !strings.HasSuffix(out.String(), "done") {
+		t.Errorf("expected final message to be kept, got %q", s.FinalMSG)
+	}
+}
+
+// errReader fails after returning its content
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+// Read
+func (r *errReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+// TestFollow verifies that readers are followed until exhausted
+func TestFollow(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput(CharSets[9], 100*time.Millisecond)
+	var tee syncBuffer
+
+	err := s.Follow(&tee, strings.NewReader("out\n"), strings.NewReader("err\n"))
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if tee.Len() != len("out\nerr\n") {
+		t.Errorf("expected output of both readers, got %q", tee.String())
+	}
+
+	readErr := errors.New("broken pipe")
+	err = s.Follow(nil, &errReader{r: strings.NewReader("out\n"), err: readErr})
+	if err != readErr {
+		t.Errorf("expected read error, got %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "broken pipe\n") || s.FinalMSG != "" {
+		t.Errorf("expected the error to be reported once, got %q and final message %q", out.String(), s.FinalMSG)
+	}
+}
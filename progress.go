@@ -0,0 +1,216 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressInterval is the minimum time between two progress updates
+// sent by a proxy.
+const progressInterval = 100 * time.Millisecond
+
+// ProgressSetter is implemented by indicators that can display the
+// progress of a transfer, like Spinner and Bar. current is the number of
+// bytes transferred so far and total is the expected number of bytes, or
+// 0 if unknown. text describes the transfer, e.g. "12.3 MiB @ 4.1 MiB/s".
+type ProgressSetter interface {
+	SetProgress(current, total int64, text string)
+}
+
+// SetProgress shows the given progress after the suffix of the spinner,
+// including the percentage when total is known. An empty text and a zero
+// total clear the progress.
+func (s *Spinner) SetProgress(current, total int64, text string) {
+	if total > 0 {
+		text = strings.TrimSpace(fmt.Sprintf("%s (%d%%)", text, percent(current, total)))
+	}
+	s.mu.Lock()
+	s.progress = text
+	s.mu.Unlock()
+}
+
+// Bar is a determinate progress bar. It is drawn by a Spinner whose only
+// frame is replaced as progress is made, so it is started and stopped
+// like one.
+type Bar struct {
+	*Spinner
+	width int
+	Fill  string // Fill is the character of the completed part of the bar
+	Empty string // Empty is the character of the remaining part of the bar
+	Head  string // Head is the character at the end of the completed part
+}
+
+// NewBar provides a pointer to an instance of Bar of the given width,
+// redrawn at the given delay.
+func NewBar(width int, d time.Duration, options ...Option) *Bar {
+	b := &Bar{
+		width: width,
+		Fill:  "=",
+		Empty: " ",
+		Head:  ">",
+	}
+	b.Spinner = New([]string{b.frame(0)}, d, options...)
+	return b
+}
+
+// SetProgress updates the bar to show current out of total, followed by
+// the percentage and the given text. The frame is replaced directly rather
+// than through UpdateCharSet, so no EventCharSet is emitted for progress.
+func (b *Bar) SetProgress(current, total int64, text string) {
+	p := percent(current, total)
+	frame := b.frame(p)
+	if total > 0 {
+		frame += fmt.Sprintf(" %3d%%", p)
+	}
+	b.mu.Lock()
+	b.chars = []string{frame}
+	b.progress = text
+	b.mu.Unlock()
+}
+
+// frame returns the bar filled up to the given percentage.
+func (b *Bar) frame(percent int) string {
+	return barFrame(b.width, b.width*percent/100, b.Fill, b.Empty, b.Head)
+}
+
+// ProxyReader is an io.Reader counting the bytes read through it and
+// reporting the progress to a ProgressSetter.
+type ProxyReader struct {
+	io.Reader
+	counter
+}
+
+// NewProxyReader returns a ProxyReader reading from r and reporting to p.
+// Set Total when the number of bytes to read is known.
+func NewProxyReader(r io.Reader, p ProgressSetter) *ProxyReader {
+	return &ProxyReader{Reader: r, counter: counter{p: p, start: time.Now()}}
+}
+
+// Read reads from the underlying reader and updates the progress.
+func (r *ProxyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.add(n, err != nil)
+	return n, err
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (r *ProxyReader) Close() error {
+	r.add(0, true)
+	if c, ok := r.Reader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ProxyWriter is an io.Writer counting the bytes written through it and
+// reporting the progress to a ProgressSetter.
+type ProxyWriter struct {
+	io.Writer
+	counter
+}
+
+// NewProxyWriter returns a ProxyWriter writing to w and reporting to p.
+// Set Total when the number of bytes to write is known.
+func NewProxyWriter(w io.Writer, p ProgressSetter) *ProxyWriter {
+	return &ProxyWriter{Writer: w, counter: counter{p: p, start: time.Now()}}
+}
+
+// Write writes to the underlying writer and updates the progress.
+func (w *ProxyWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.add(n, err != nil)
+	return n, err
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (w *ProxyWriter) Close() error {
+	w.add(0, true)
+	if c, ok := w.Writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// counter counts the bytes transferred by a proxy.
+type counter struct {
+	mu      sync.Mutex
+	p       ProgressSetter
+	start   time.Time
+	updated time.Time
+	n       int64
+	Total   int64 // Total is the expected number of bytes, 0 or less if unknown
+}
+
+// Count returns the number of bytes transferred so far.
+func (c *counter) Count() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// add counts n more bytes and reports the progress, at most every
+// progressInterval unless force is set.
+func (c *counter) add(n int, force bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.n += int64(n)
+	now := time.Now()
+	if !force && now.Sub(c.updated) < progressInterval && (c.Total <= 0 || c.n < c.Total) {
+		return
+	}
+	c.updated = now
+
+	text := formatBytes(c.n)
+	if elapsed := now.Sub(c.start).Seconds(); elapsed > 0 {
+		text += " @ " + formatBytes(int64(float64(c.n)/elapsed)) + "/s"
+	}
+	total := c.Total
+	if total < 0 {
+		total = 0
+	}
+	c.p.SetProgress(c.n, total, text)
+}
+
+// percent returns current as a percentage of total, between 0 and 100.
+func percent(current, total int64) int {
+	if total <= 0 || current <= 0 {
+		return 0
+	}
+	if current >= total {
+		return 100
+	}
+	return int(current * 100 / total)
+}
+
+// formatBytes returns n as a human readable size using binary units,
+// e.g. "12.3 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 5; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
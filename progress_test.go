@@ -0,0 +1,133 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// progressRecorder records the last progress reported to it
+type progressRecorder struct {
+	current, total int64
+	text           string
+	calls          int
+}
+
+// SetProgress
+func (p *progressRecorder) SetProgress(current, total int64, text string) {
+	p.current, p.total, p.text = current, total, text
+	p.calls++
+}
+
+// TestProxyReader verifies that the bytes read are reported
+func TestProxyReader(t *testing.T) {
+	var p progressRecorder
+	data := strings.Repeat("x", 3*1024*1024)
+	r := NewProxyReader(strings.NewReader(data), &p)
+	r.Total = int64(len(data))
+
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Count() != int64(len(data)) || p.current != int64(len(data)) || p.total != int64(len(data)) {
+		t.Errorf("expected all bytes to be reported, got count=%d current=%d total=%d", r.Count(), p.current, p.total)
+	}
+	if !strings.HasPrefix(p.text, "3.0 MiB @ ") || !strings.HasSuffix(p.text, "/s") {
+		t.Errorf("unexpected progress text %q", p.text)
+	}
+	if p.calls > 10 {
+		t.Errorf("expected progress updates to be throttled, got %d", p.calls)
+	}
+}
+
+// TestProxyWriter verifies that the bytes written are reported
+func TestProxyWriter(t *testing.T) {
+	s, _ := withOutput(CharSets[9], 100*time.Millisecond)
+	var out bytes.Buffer
+	w := NewProxyWriter(&out, s)
+	w.Write([]byte("hello"))
+	w.Close()
+	if out.String() != "hello" {
+		t.Errorf("expected data to be written, got %q", out.String())
+	}
+	if !strings.HasPrefix(s.progress, "5 B @ ") {
+		t.Errorf("expected progress to be shown, got %q", s.progress)
+	}
+}
+
+// TestSpinnerSetProgress verifies that progress is shown after the suffix
+func TestSpinnerSetProgress(t *testing.T) {
+	s, out := withOutput([]string{"a"}, 100*time.Millisecond)
+	s.Suffix = " Downloading"
+	s.SetProgress(512, 2048, "512 B @ 1.0 KiB/s")
+	s.draw("a")
+	s.flush()
+	if want := " Downloading 512 B @ 1.0 KiB/s (25%)"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("expected progress after the suffix. got=%q want=%q", out.String(), want)
+	}
+}
+
+// TestBar verifies that the bar is filled according to the progress
+func TestBar(t *testing.T) {
+	b := NewBar(10, 100*time.Millisecond, WithWriter(ioutil.Discard))
+	tests := []struct {
+		current, total int64
+		expected       string
+	}{
+		{0, 0, "[          ]"},
+		{0, 100, "[          ]   0%"},
+		{45, 100, "[====>     ]  45%"},
+		{99, 100, "[=========>]  99%"},
+		{100, 100, "[==========] 100%"},
+	}
+
+	changes := 0
+	b.OnCharSetChange(func([]string) { changes++ })
+
+	for _, test := range tests {
+		b.SetProgress(test.current, test.total, "")
+		if b.chars[0] != test.expected {
+			t.Errorf("%d/%d: expected %q, got %q", test.current, test.total, test.expected, b.chars[0])
+		}
+	}
+	if changes != 0 {
+		t.Errorf("expected no character set change for progress, got %d", changes)
+	}
+}
+
+// TestFormatBytes verifies sizes are human readable
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{12*1024*1024 + 300*1024, "12.3 MiB"},
+		{5 * 1024 * 1024 * 1024, "5.0 GiB"},
+	}
+
+	for _, test := range tests {
+		if got := formatBytes(test.n); got != test.expected {
+			t.Errorf("%d: expected %q, got %q", test.n, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"os"
+	"time"
+)
+
+// Frame is a frame of the animation as returned by Render, to draw the
+// spinner with a custom renderer instead of Start and Stop.
+type Frame struct {
+	Index    int           // Index of the frame in the character set
+	Text     string        // Text is the prefix, colored frame and suffix
+	Plain    string        // Plain is Text without color
+	Width    int           // Width is the number of terminal cells taken by Plain
+	Deadline time.Duration // Deadline is the time since the start of the animation the next frame is due
+}
+
+// Render returns the frame with the given index, counted from the start
+// of the animation and wrapping around the character set, along with the
+// prefix, suffix and progress of the spinner. It doesn't write anything or
+// change the spinner, so it may be called whether the spinner is started
+// or not.
+func (s *Spinner) Render(index int) Frame {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.render(index)
+}
+
+// RenderAt returns the frame due the given time after the start of the
+// animation, see Render.
+func (s *Spinner) RenderAt(elapsed time.Duration) Frame {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.render(s.frameAt(elapsed))
+}
+
+// render returns the frame with the given index. Caller must already hold
+// s.lock.
+func (s *Spinner) render(index int) Frame {
+	if index < 0 {
+		index = 0
+	}
+	f := Frame{Deadline: s.deadline(index)}
+	var frame string
+	if len(s.chars) > 0 {
+		f.Index = index % len(s.chars)
+		frame = s.chars[f.Index]
+	}
+	f.Text, f.Plain = s.line(frame)
+	f.Width = displayWidth(f.Plain)
+	return f
+}
+
+// line returns the line showing the given frame along with the prefix and
+// suffix, colored and plain. Caller must already hold s.lock.
+func (s *Spinner) line(frame string) (string, string) {
+	suffix := s.suffix()
+	return s.Prefix + s.colorFrame(frame) + suffix, s.Prefix + frame + suffix
+}
+
+// colorFrame returns the given frame in the color of the spinner. Caller
+// must already hold s.lock.
+func (s *Spinner) colorFrame(frame string) string {
+	if isWindows && s.Writer == os.Stderr {
+		return frame
+	}
+	return s.color(frame)
+}
+
+// suffix returns the suffix followed by the progress, if any. Caller must
+// already hold s.lock.
+func (s *Spinner) suffix() string {
+	if s.progress != "" {
+		return s.Suffix + " " + s.progress
+	}
+	return s.Suffix
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRender verifies that Render returns the frame with the given index
+// along with the prefix and suffix
+func TestRender(t *testing.T) {
+	s, out := withOutput([]string{"a", "bb", "世"}, 100*time.Millisecond)
+	s.Prefix = "> "
+	s.Suffix = " working"
+
+	tests := []struct {
+		index    int
+		want     int
+		plain    string
+		width    int
+		deadline time.Duration
+	}{
+		{0, 0, "> a working", 11, 100 * time.Millisecond},
+		{1, 1, "> bb working", 12, 200 * time.Millisecond},
+		{2, 2, "> 世 working", 12, 300 * time.Millisecond},
+		{4, 1, "> bb working", 12, 500 * time.Millisecond},
+		{-1, 0, "> a working", 11, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		f := s.Render(tt.index)
+		if f.Index != tt.want {
+			t.Errorf("Render(%d): got index %d, want %d", tt.index, f.Index, tt.want)
+		}
+		if f.Plain != tt.plain {
+			t.Errorf("Render(%d): got %q, want %q", tt.index, f.Plain, tt.plain)
+		}
+		if !strings.Contains(f.Text, s.chars[tt.want]) {
+			t.Errorf("Render(%d): text %q doesn't contain the frame", tt.index, f.Text)
+		}
+		if f.Width != tt.width {
+			t.Errorf("Render(%d): got width %d, want %d", tt.index, f.Width, tt.width)
+		}
+		if f.Deadline != tt.deadline {
+			t.Errorf("Render(%d): got deadline %s, want %s", tt.index, f.Deadline, tt.deadline)
+		}
+	}
+
+	if out.Len() != 0 || s.Active() {
+		t.Errorf("Render wrote %q or started the spinner", out.String())
+	}
+}
+
+// TestRenderAt verifies that RenderAt returns the frame due at the given
+// time
+func TestRenderAt(t *testing.T) {
+	s := New([]string{"a", "b", "c"}, 100*time.Millisecond)
+
+	tests := []struct {
+		elapsed time.Duration
+		plain   string
+	}{
+		{0, "a"},
+		{99 * time.Millisecond, "a"},
+		{100 * time.Millisecond, "b"},
+		{250 * time.Millisecond, "c"},
+		{300 * time.Millisecond, "a"},
+	}
+
+	for _, tt := range tests {
+		if got := s.RenderAt(tt.elapsed).Plain; got != tt.plain {
+			t.Errorf("RenderAt(%s): got %q, want %q", tt.elapsed, got, tt.plain)
+		}
+	}
+}
+
+// TestRenderMatchesDrawnLine verifies that the spinner draws the line
+// returned by Render
+func TestRenderMatchesDrawnLine(t *testing.T) {
+	withTerminal(t)
+	s, _ := withOutput([]string{"a"}, 10*time.Millisecond)
+	s.Suffix = " working"
+	s.SetProgress(1, 4, "files")
+	drawn := make(chan struct{}, 1)
+	s.PostUpdate = func(*Spinner) {
+		select {
+		case drawn <- struct{}{}:
+		default:
+		}
+	}
+
+	s.Start()
+	<-drawn
+	s.Stop()
+
+	if want := "\r" + s.Render(0).Text; s.LastOutput != want {
+		t.Errorf("got %q, want %q", s.LastOutput, want)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Status is the outcome of a step, shown as a symbol at the start of
+// its status line.
+type Status int
+
+// Available statuses
+const (
+	Success Status = iota
+	Failure
+	Warning
+	Skipped
+)
+
+// statusSymbols holds the symbol and color of each status
+var statusSymbols = map[Status]struct {
+	symbol string
+	color  color.Attribute
+}{
+	Success: {"✔", color.FgGreen},
+	Failure: {"✖", color.FgRed},
+	Warning: {"⚠", color.FgYellow},
+	Skipped: {"-", color.FgHiBlack},
+}
+
+// String returns the colored symbol of the status.
+func (st Status) String() string {
+	sym, ok := statusSymbols[st]
+	if !ok {
+		return "?"
+	}
+	return color.New(sym.color).Sprint(sym.symbol)
+}
+
+// statusLine formats a line reporting the outcome of a step, e.g.
+// "✔ Deploying (1.2s)" or "✖ Deploying: connection refused (1.2s)".
+func statusLine(st Status, msg string, err error, elapsed time.Duration) string {
+	if err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, err)
+	}
+	return fmt.Sprintf("%s %s (%s)\n", st, msg, formatDuration(elapsed))
+}
+
+// formatDuration rounds d to a precision suitable for status lines.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// Run starts a spinner showing msg, calls fn and stops the spinner with a
+// status line reporting the outcome and the elapsed time. The spinner is
+// created with CharSets[14] and the given options. The error returned by
+// fn is returned unchanged. A panic in fn is recovered and returned as an
+// error.
+func Run(ctx context.Context, msg string, fn func(context.Context) error, options ...Option) error {
+	return New(CharSets[14], 100*time.Millisecond, options...).Run(ctx, msg, fn)
+}
+
+// Run shows msg as the suffix of the spinner while calling fn, then stops
+// the spinner with a status line reporting the outcome and the elapsed
+// time in place of FinalMSG. The status line is written even if the
+// spinner couldn't be started, e.g. when not writing to a terminal. The
+// error returned by fn is returned unchanged. A panic in fn is recovered
+// and returned as an error.
+func (s *Spinner) Run(ctx context.Context, msg string, fn func(context.Context) error) (err error) {
+	s.mu.Lock()
+	suffix, finalMsg := s.Suffix, s.FinalMSG
+	s.Suffix = " " + msg
+	s.mu.Unlock()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		st := Success
+		if err != nil {
+			st = Failure
+		}
+		s.stopWithStatus(st, msg, err, time.Since(start))
+
+		s.mu.Lock()
+		s.Suffix, s.FinalMSG = suffix, finalMsg
+		s.mu.Unlock()
+	}()
+
+	s.Start()
+	return fn(ctx)
+}
+
+// stopWithStatus stops the spinner and writes the status line of the
+// outcome in place of FinalMSG, whether or not the spinner was active. In
+// accessible mode the outcome is announced instead. A status event is sent
+// to the listeners.
+func (s *Spinner) stopWithStatus(st Status, msg string, err error, elapsed time.Duration) {
+	defer s.dispatch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emit(Event{
+		Type:    EventStatus,
+		Elapsed: elapsed,
+		Message: msg,
+		Status:  st,
+		Err:     err,
+	})
+
+	if s.accessible {
+		s.FinalMSG = ""
+		s.stop(false)
+		s.announceStatus(st, msg, err, elapsed)
+		s.flush()
+		return
+	}
+	line := statusLine(st, msg, err, elapsed)
+	s.FinalMSG = line
+	if s.active.get() {
+		s.stop(true)
+		return
+	}
+	s.buf.WriteString(line)
+	s.flush()
+}
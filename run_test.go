@@ -0,0 +1,83 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRun verifies the status line and that the error is returned unchanged
+func TestRun(t *testing.T) {
+	s, out := withOutput(CharSets[9], 100*time.Millisecond)
+	s.Suffix = " idle"
+	s.FinalMSG = "bye"
+
+	if err := s.Run(context.Background(), "Deploying", func(context.Context) error { return nil }); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if !strings.HasPrefix(out.String(), Success.String()+" Deploying (") {
+		t.Errorf("expected success status line, got %q", out.String())
+	}
+
+	out.Reset()
+	errDeploy := errors.New("connection refused")
+	err := s.Run(context.Background(), "Deploying", func(context.Context) error { return errDeploy })
+	if err != errDeploy {
+		t.Errorf("expected the error to be returned unchanged, got %v", err)
+	}
+	if !strings.HasPrefix(out.String(), Failure.String()+" Deploying: connection refused (") {
+		t.Errorf("expected failure status line, got %q", out.String())
+	}
+
+	if s.Suffix != " idle" || s.FinalMSG != "bye" {
+		t.Errorf("expected suffix and final message to be restored, got %q %q", s.Suffix, s.FinalMSG)
+	}
+}
+
+// TestRunPanic verifies that a panic is recovered and reported
+func TestRunPanic(t *testing.T) {
+	var out syncBuffer
+	err := Run(context.Background(), "Migrating", func(context.Context) error {
+		panic("boom")
+	}, WithWriter(&out))
+	if err == nil || err.Error() != "panic: boom" {
+		t.Errorf("expected the panic as an error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Migrating: panic: boom") {
+		t.Errorf("expected the panic in the status line, got %q", out.String())
+	}
+}
+
+// TestFormatDuration verifies durations are rounded for status lines
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{1234567 * time.Nanosecond, "1ms"},
+		{1234 * time.Millisecond, "1.2s"},
+		{83 * time.Second, "1m23s"},
+	}
+
+	for _, test := range tests {
+		if got := formatDuration(test.d); got != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.d, test.expected, got)
+		}
+	}
+}
@@ -16,6 +16,7 @@
 package spinner
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -25,6 +26,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 	"unicode/utf8"
 
@@ -32,8 +35,8 @@ import (
 	"golang.org/x/term"
 )
 
-// errInvalidColor is returned when attempting to set an invalid color
-var errInvalidColor = errors.New("invalid color")
+// ErrInvalidColor is returned when attempting to set an invalid color
+var ErrInvalidColor = errors.New("invalid color")
 
 // validColors holds an array of the only colors allowed
 var validColors = map[string]bool{
@@ -179,40 +182,79 @@ type Spinner struct {
 	mu              *sync.RWMutex
 	Delay           time.Duration                 // Delay is the speed of the indicator
 	chars           []string                      // chars holds the chosen character set
+	timing          []float64                     // timing holds the duration of each frame as a multiple of Delay, see WithTiming
+	autoPad         bool                          // autoPad pads the frames to the same width, see WithAutoPad
+	fallback        []string                      // fallback replaces chars when they can't be rendered, see WithFallback
+	choice          CharSetChoice                 // choice records whether the fallback is used
+	accessible      bool                          // accessible writes lines instead of animating, see WithAccessible
+	announced       string                        // announced is the status last written in accessible mode
 	Prefix          string                        // Prefix is the text preppended to the indicator
 	Suffix          string                        // Suffix is the text appended to the indicator
 	FinalMSG        string                        // string displayed after Stop() is called
 	lastOutputPlain string                        // last character(set) written
 	LastOutput      string                        // last character(set) written with colors
+	lastPrefix      string                        // prefix written with the last frame
+	lastSuffix      string                        // suffix written with the last frame
+	lastFrame       string                        // last frame written
+	lastFrameColor  string                        // last frame written with colors
+	redraw          bool                          // forces the next frame to rewrite the whole line
+	progress        string                        // progress segment displayed after the suffix, see SetProgress
+	stepStart       time.Time                     // time the current step started, see Persist
+	startTime       time.Time                     // time the spinner was started, see Elapsed
+	pausedAt        time.Time                     // time the spinner was paused or stopped
+	paused          bool                          // paused indicates the animation is held, see Pause
+	prompting       bool                          // prompting indicates the pause lasts until a prompt is answered, see NewSuspendReader
+	frameIndex      int                           // frameIndex is the index of the next frame to draw
 	color           func(a ...interface{}) string // default color is white
 	Writer          io.Writer                     // to make testing better, exported so users have access. Use `WithWriter` to update after initialization.
 	WriterFile      *os.File                      // writer as file to allow terminal check
-	active          bool                          // active holds the state of the spinner
-	enabled         bool                          // indicates whether the spinner is enabled or not
-	stopChan        chan struct{}                 // stopChan is a channel used to stop the indicator
+	active          flag                          // active holds the state of the spinner
+	enabled         flag                          // indicates whether the spinner is enabled or not
+	stopChan        chan struct{}                 // stopChan is closed to stop the indicator
 	HideCursor      bool                          // hideCursor determines if the cursor is visible
-	PreUpdate       func(s *Spinner)              // will be triggered before every spinner update
-	PostUpdate      func(s *Spinner)              // will be triggered after every spinner update
+	SyncOutput      bool                          // SyncOutput wraps each update in DEC mode 2026 so it appears atomically
+	buf             bytes.Buffer                  // buf assembles the output of a single update
+	PreUpdate       func(s *Spinner)              // will be triggered before every spinner update, without the spinner locked
+	PreRender       func(f *FrameContext)         // will be triggered before every spinner update with the frame to draw, without the spinner locked
+	PostUpdate      func(s *Spinner)              // will be triggered after every spinner update, without the spinner locked
+	listeners       []func(Event)                 // listeners are called for lifecycle events, see OnEvent
+	events          []Event                       // events waiting to be dispatched to the listeners
+	err             error                         // last error returned by the Writer, see Err
+	configErr       error                         // first error found applying the options, see ConfigErr
 }
 
 // New provides a pointer to an instance of Spinner with the supplied options.
+// Character sets from CharSets are timed as given in CharSetTimings.
 func New(cs []string, d time.Duration, options ...Option) *Spinner {
 	s := &Spinner{
 		Delay:      d,
 		chars:      cs,
+		timing:     charSetTiming(cs),
 		color:      color.New(color.FgWhite).SprintFunc(),
 		mu:         &sync.RWMutex{},
 		Writer:     color.Output,
 		WriterFile: os.Stdout, // matches color.Output
 		stopChan:   make(chan struct{}, 1),
-		active:     false,
-		enabled:    true,
 		HideCursor: true,
+		accessible: accessibleFromEnv(),
+
+		SyncOutput: supportsSynchronizedOutput(),
 	}
+	s.enabled.set(true)
 
 	for _, option := range options {
 		option(s)
 	}
+	if cs := s.chooseCharSet(s.chars); s.choice.Fallback {
+		s.chars, s.timing = cs, charSetTiming(cs)
+	}
+	if s.autoPad {
+		s.chars = Pad(s.chars)
+	}
+	if s.accessible {
+		s.HideCursor = false
+		s.SyncOutput = false
+	}
 
 	return s
 }
@@ -221,18 +263,107 @@ func New(cs []string, d time.Duration, options ...Option) *Spinner {
 // a given configuration.
 type Option func(*Spinner)
 
-// Options contains fields to configure the spinner.
+// Options contains fields to configure the spinner, see NewWithOptions.
 type Options struct {
-	Color      string
-	Suffix     string
-	FinalMSG   string
-	HideCursor bool
+	CharSet     []string              // CharSet holds the frames, used rather than CharSetName when set
+	CharSetName string                // CharSetName is the name or index of a set in CharSets, see LookupCharSet
+	Delay       time.Duration         // Delay is the speed of the indicator, must be positive
+	Timing      []float64             // Timing holds the duration of each frame, see WithTiming
+	Fallback    []string              // Fallback replaces the frames when they can't be rendered, see WithFallback
+	AutoPad     bool                  // AutoPad pads the frames to the same width, see WithAutoPad
+	Color       string                // Color of the frames
+	Colors      []string              // Colors holds more colors or attributes of the frames, e.g. "bold"
+	Prefix      string                // Prefix is the text preppended to the indicator
+	Suffix      string                // Suffix is the text appended to the indicator
+	FinalMSG    string                // string displayed after Stop() is called
+	HideCursor  *bool                 // HideCursor overrides hiding the cursor, hidden by default as with New
+	Accessible  bool                  // Accessible enables accessible mode, see WithAccessible
+	SyncOutput  *bool                 // SyncOutput overrides the detection of synchronized output when set, see WithSyncOutput
+	Writer      io.Writer             // Writer the spinner is written to, see WithWriter
+	WriterFile  *os.File              // WriterFile the spinner is written to, see WithWriterFile
+	PreUpdate   func(s *Spinner)      // will be triggered before every spinner update, without the spinner locked
+	PreRender   func(f *FrameContext) // will be triggered before every spinner update with the frame to draw, without the spinner locked
+	PostUpdate  func(s *Spinner)      // will be triggered after every spinner update, without the spinner locked
 }
 
-// WithColor adds the given color to the spinner.
+// NewWithOptions provides a pointer to an instance of Spinner configured
+// with the given options. It returns an error if the character set is
+// empty or unknown, the delay isn't positive or a color is invalid.
+func NewWithOptions(o Options) (*Spinner, error) {
+	cs := o.CharSet
+	if cs == nil && o.CharSetName != "" {
+		var err error
+		if cs, err = LookupCharSet(o.CharSetName); err != nil {
+			return nil, err
+		}
+	}
+	if len(cs) == 0 || (o.Fallback != nil && len(o.Fallback) == 0) {
+		return nil, ErrNoFrames
+	}
+	if o.Delay <= 0 {
+		return nil, ErrInvalidDelay
+	}
+
+	options := []Option{
+		WithSuffix(o.Suffix),
+		WithFinalMSG(o.FinalMSG),
+		func(s *Spinner) {
+			s.Prefix = o.Prefix
+			s.PreUpdate, s.PreRender, s.PostUpdate = o.PreUpdate, o.PreRender, o.PostUpdate
+		},
+	}
+	colors := o.Colors
+	if o.Color != "" {
+		colors = append([]string{o.Color}, colors...)
+	}
+	if len(colors) > 0 {
+		options = append(options, withColors(colors))
+	}
+	if o.Writer != nil {
+		options = append(options, WithWriter(o.Writer))
+	}
+	if o.WriterFile != nil {
+		options = append(options, WithWriterFile(o.WriterFile))
+	}
+	if o.Timing != nil {
+		options = append(options, WithTiming(o.Timing))
+	}
+	if o.Fallback != nil {
+		options = append(options, WithFallback(o.Fallback))
+	}
+	if o.AutoPad {
+		options = append(options, WithAutoPad())
+	}
+	if o.Accessible {
+		options = append(options, WithAccessible(true))
+	}
+	if o.HideCursor != nil {
+		options = append(options, WithHiddenCursor(*o.HideCursor))
+	}
+	if o.SyncOutput != nil {
+		options = append(options, WithSyncOutput(*o.SyncOutput))
+	}
+
+	s := New(cs, o.Delay, options...)
+	if err := s.ConfigErr(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WithColor adds the given color to the spinner. An invalid color is
+// reported by ConfigErr.
 func WithColor(color string) Option {
+	return withColors([]string{color})
+}
+
+// withColors adds the given colors to the spinner, recording an invalid
+// color for ConfigErr.
+func withColors(colors []string) Option {
 	return func(s *Spinner) {
-		s.Color(color)
+		if err := s.Color(colors...); err != nil {
+			s.setConfigErr(err)
+		}
 	}
 }
 
@@ -260,6 +391,14 @@ func WithHiddenCursor(hideCursor bool) Option {
 	}
 }
 
+// WithSyncOutput enables or disables wrapping each
+// update in the terminal's synchronized output mode.
+func WithSyncOutput(enabled bool) Option {
+	return func(s *Spinner) {
+		s.SyncOutput = enabled
+	}
+}
+
 // WithWriter adds the given writer to the spinner. This
 // function should be favored over directly assigning to
 // the struct value. Assumes it is not working on a terminal
@@ -288,38 +427,71 @@ func WithWriterFile(f *os.File) Option {
 	}
 }
 
-// Active will return whether or not the spinner is currently active.
+// Active will return whether or not the spinner is currently active. It
+// doesn't take the lock, so it may be called while the spinner is held
+// with Lock.
 func (s *Spinner) Active() bool {
-	return s.active
+	return s.active.get()
 }
 
-// Enabled returns whether or not the spinner is enabled.
+// Enabled returns whether or not the spinner is enabled. It doesn't take
+// the lock, so it may be called while the spinner is held with Lock.
 func (s *Spinner) Enabled() bool {
-	return s.enabled
+	return s.enabled.get()
 }
 
 // Enable enables and restarts the spinner
 func (s *Spinner) Enable() {
-	s.enabled = true
+	s.mu.Lock()
+	s.enabled.set(true)
+	s.mu.Unlock()
 	s.Restart()
 }
 
 // Disable stops and disables the spinner
 func (s *Spinner) Disable() {
-	s.enabled = false
+	s.mu.Lock()
+	s.enabled.set(false)
+	s.mu.Unlock()
 	s.Stop()
 }
 
+// flag is a boolean read without the lock by the getters. It is only
+// changed with the lock held, so reads under the lock see a stable value.
+type flag int32
+
+// get returns the value of the flag.
+func (f *flag) get() bool {
+	return atomic.LoadInt32((*int32)(f)) == 1
+}
+
+// set changes the value of the flag.
+func (f *flag) set(v bool) {
+	var i int32
+	if v {
+		i = 1
+	}
+	atomic.StoreInt32((*int32)(f), i)
+}
+
 // Start will start the indicator.
 func (s *Spinner) Start() {
 	s.mu.Lock()
-	if s.active || !s.enabled || !isRunningInTerminal(s) {
+	if !s.active.get() {
+		// steps are timed even when not animating, see Persist
+		s.startTime = time.Now()
+		s.stepStart = s.startTime
+		s.pausedAt = time.Time{}
+		s.err = nil
+	}
+	if s.active.get() || !s.enabled.get() || (!s.accessible && !isRunningInTerminal(s)) {
 		s.mu.Unlock()
 		return
 	}
 	if s.HideCursor && !isWindowsTerminalOnWindows {
 		// hides the cursor
-		fmt.Fprint(s.Writer, "\033[?25l")
+		s.buf.WriteString("\033[?25l")
+		s.flush()
 	}
 	// Disable colors for simple Windows CMD or Powershell
 	// as they can not recognize them
@@ -327,134 +499,445 @@ func (s *Spinner) Start() {
 		color.NoColor = true
 	}
 
-	s.active = true
+	s.active.set(true)
+	s.paused = false
+	s.frameIndex = 0
+	// each run has its own stop channel so a goroutine left over from a
+	// previous run can't keep drawing after a quick Stop and Start
+	stop := make(chan struct{})
+	s.stopChan = stop
+	if s.accessible {
+		s.announced = s.status()
+		s.announce("Started", s.announced)
+		s.flush()
+	}
+	s.emit(Event{Type: EventStart})
 	s.mu.Unlock()
+	s.dispatch()
+
+	if s.accessible {
+		go s.announceChanges(stop, announceInterval)
+		return
+	}
 
 	go func() {
 		for {
-			for i := 0; i < len(s.chars); i++ {
-				select {
-				case <-s.stopChan:
+			s.mu.Lock()
+			if !s.active.get() || s.stopChan != stop {
+				s.mu.Unlock()
+				return
+			}
+			if s.paused || len(s.chars) == 0 {
+				// nothing to draw, wait for frames or Resume
+				delay := clampDelay(s.Delay)
+				s.mu.Unlock()
+				s.dispatch()
+				if !wait(stop, delay) {
 					return
-				default:
-					s.mu.Lock()
-					if !s.active {
-						s.mu.Unlock()
-						return
-					}
-					if !isWindowsTerminalOnWindows {
-						s.erase()
-					}
-
-					if s.PreUpdate != nil {
-						s.PreUpdate(s)
-					}
-
-					var outColor string
-					if isWindows {
-						if s.Writer == os.Stderr {
-							outColor = fmt.Sprintf("\r%s%s%s", s.Prefix, s.chars[i], s.Suffix)
-						} else {
-							outColor = fmt.Sprintf("\r%s%s%s", s.Prefix, s.color(s.chars[i]), s.Suffix)
-						}
-					} else {
-						outColor = fmt.Sprintf("\r%s%s%s", s.Prefix, s.color(s.chars[i]), s.Suffix)
-					}
-					outPlain := fmt.Sprintf("\r%s%s%s", s.Prefix, s.chars[i], s.Suffix)
-					fmt.Fprint(s.Writer, outColor)
-					s.lastOutputPlain = outPlain
-					s.LastOutput = outColor
-					delay := s.Delay
-
-					if s.PostUpdate != nil {
-						s.PostUpdate(s)
-					}
-
-					s.mu.Unlock()
-					time.Sleep(delay)
 				}
+				continue
+			}
+			s.frameIndex %= len(s.chars)
+			f := FrameContext{
+				Index:  s.frameIndex,
+				Frame:  s.chars[s.frameIndex],
+				Prefix: s.Prefix,
+				Suffix: s.Suffix,
+				Delay:  s.Delay,
+			}
+			preUpdate, preRender, postUpdate := s.PreUpdate, s.PreRender, s.PostUpdate
+			s.mu.Unlock()
+
+			if preUpdate != nil {
+				preUpdate(s)
+			}
+			before := f
+			if preRender != nil {
+				preRender(&f)
+			}
+
+			s.mu.Lock()
+			if !s.active.get() || s.stopChan != stop {
+				s.mu.Unlock()
+				s.dispatch()
+				return
+			}
+			delay := clampDelay(s.Delay)
+			if !s.paused {
+				frame := f.Frame
+				if frame == before.Frame && len(s.chars) > 0 {
+					// the character set may have been changed by a hook
+					s.frameIndex %= len(s.chars)
+					frame = s.chars[s.frameIndex]
+				}
+				delay = s.applyFrame(before, f)
+				s.draw(frame)
+				s.flush()
+				if len(s.chars) > 0 {
+					s.frameIndex = (s.frameIndex + 1) % len(s.chars)
+				}
+			}
+			s.mu.Unlock()
+
+			if postUpdate != nil {
+				postUpdate(s)
+			}
+			s.dispatch()
+
+			if !wait(stop, delay) {
+				return
 			}
 		}
 	}()
 }
 
+// wait waits for the given delay and returns false if stop was closed
+// in the meantime.
+func wait(stop chan struct{}, delay time.Duration) bool {
+	select {
+	case <-stop:
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// FrameContext describes the frame about to be drawn. It is given to the
+// PreRender hook, which may change it before the frame is drawn.
+type FrameContext struct {
+	Index  int           // Index of the frame in the character set
+	Frame  string        // Frame is the text drawn as the indicator for this frame only
+	Prefix string        // Prefix replaces the Prefix of the spinner when changed
+	Suffix string        // Suffix replaces the Suffix of the spinner when changed
+	Color  []string      // Color replaces the color of the spinner when set, see Color
+	Delay  time.Duration // Delay replaces the Delay of the spinner when changed
+}
+
+// applyFrame applies the changes the PreRender hook made to the frame
+// context, given as before and after the call, and returns the delay until
+// the next frame. Fields left unchanged don't overwrite changes made by
+// calling methods of the spinner from the hooks. Caller must already hold
+// s.lock.
+func (s *Spinner) applyFrame(before, f FrameContext) time.Duration {
+	if f.Prefix != before.Prefix {
+		s.Prefix = f.Prefix
+	}
+	if f.Suffix != before.Suffix {
+		s.Suffix = f.Suffix
+	}
+	if f.Color != nil {
+		if fn, err := colorFunc(f.Color); err == nil {
+			s.color = fn
+		}
+	}
+	if f.Delay > 0 && f.Delay != before.Delay {
+		s.Delay = f.Delay
+	}
+	return s.frameDelay(s.frameIndex)
+}
+
+// draw writes the given frame along with the prefix and suffix. When the
+// previous line is still on screen only the cells that changed are
+// rewritten, and nothing is written at all if the line is unchanged.
+// Output is assembled in s.buf and written by flush.
+// Caller must already hold s.lock.
+func (s *Spinner) draw(frame string) {
+	frameColor, suffix := s.colorFrame(frame), s.suffix()
+	outColor, outPlain := s.line(frame)
+	outColor, outPlain = "\r"+outColor, "\r"+outPlain
+
+	if s.canRedrawFrame(frame, suffix, outPlain) {
+		if frameColor != s.lastFrameColor {
+			s.buf.WriteString("\r")
+			if n := displayWidth(s.Prefix); n > 0 {
+				fmt.Fprintf(&s.buf, "\033[%dC", n)
+			}
+			s.buf.WriteString(frameColor)
+			if n := displayWidth(suffix); n > 0 {
+				fmt.Fprintf(&s.buf, "\033[%dC", n)
+			}
+		}
+	} else {
+		if !isWindowsTerminalOnWindows {
+			s.erase()
+		}
+		s.buf.WriteString(outColor)
+	}
+
+	s.lastOutputPlain = outPlain
+	s.LastOutput = outColor
+	s.lastPrefix = s.Prefix
+	s.lastSuffix = suffix
+	s.lastFrame = frame
+	s.lastFrameColor = frameColor
+	s.redraw = false
+}
+
+// canRedrawFrame reports whether the line currently on screen only
+// differs from the new one in its frame cells, so the frame can be
+// overwritten in place. Caller must already hold s.lock.
+func (s *Spinner) canRedrawFrame(frame, suffix, outPlain string) bool {
+	if s.redraw || s.lastOutputPlain == "" || (isWindows && !isWindowsTerminalOnWindows) {
+		return false
+	}
+	if s.Prefix != s.lastPrefix || suffix != s.lastSuffix {
+		return false
+	}
+	if displayWidth(frame) != displayWidth(s.lastFrame) {
+		return false
+	}
+	return computeNumberOfLinesNeededToPrintString(outPlain) == 1
+}
+
 // Stop stops the indicator.
 func (s *Spinner) Stop() {
+	defer s.dispatch()
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.active {
-		s.active = false
-		if s.HideCursor && !isWindowsTerminalOnWindows {
+	s.stop(true)
+}
+
+// stop stops the indicator, announcing it finished in accessible mode if
+// finished is set. Caller must already hold s.lock.
+func (s *Spinner) stop(finished bool) {
+	if s.pausedAt.IsZero() {
+		s.pausedAt = time.Now()
+	}
+	if s.active.get() {
+		s.active.set(false)
+		if s.HideCursor && !isWindowsTerminalOnWindows && !s.paused {
 			// makes the cursor visible
-			fmt.Fprint(s.Writer, "\033[?25h")
+			s.buf.WriteString("\033[?25h")
 		}
+		s.paused = false
+		s.prompting = false
 		s.erase()
+		if s.accessible && finished {
+			var text []string
+			if st := s.status(); st != "" {
+				text = append(text, st)
+			}
+			text = append(text, spokenDuration(s.pausedAt.Sub(s.startTime)))
+			s.announce("Finished", strings.Join(text, ", "))
+		}
 		if s.FinalMSG != "" {
 			if isWindowsTerminalOnWindows {
-				fmt.Fprint(s.Writer, "\r", s.FinalMSG)
-			} else {
-				fmt.Fprint(s.Writer, s.FinalMSG)
+				s.buf.WriteString("\r")
 			}
+			s.buf.WriteString(s.FinalMSG)
 		}
-		s.stopChan <- struct{}{}
+		s.flush()
+		s.emit(Event{Type: EventStop, Elapsed: s.pausedAt.Sub(s.startTime), Message: s.FinalMSG})
+		close(s.stopChan)
+	}
+}
+
+// Pause erases the spinner line and holds the animation, e.g. while
+// prompting the user for input. The cursor is made visible if it was
+// hidden. The frame, text and elapsed time are kept so Resume continues
+// from where the spinner was paused.
+func (s *Spinner) Pause() {
+	defer s.dispatch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active.get() || s.paused {
+		return
 	}
+	s.paused = true
+	s.pausedAt = time.Now()
+	s.erase()
+	if s.HideCursor && !isWindowsTerminalOnWindows {
+		// makes the cursor visible
+		s.buf.WriteString("\033[?25h")
+	}
+	s.flush()
+	s.emit(Event{Type: EventPause})
+}
+
+// Resume redraws the spinner line and continues the animation held by
+// Pause. The time spent paused isn't counted in the elapsed time.
+func (s *Spinner) Resume() {
+	defer s.dispatch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active.get() || !s.paused {
+		return
+	}
+	paused := time.Since(s.pausedAt)
+	s.startTime = s.startTime.Add(paused)
+	s.stepStart = s.stepStart.Add(paused)
+	s.pausedAt = time.Time{}
+	s.paused = false
+	s.prompting = false
+
+	if s.HideCursor && !isWindowsTerminalOnWindows {
+		// hides the cursor
+		s.buf.WriteString("\033[?25l")
+	}
+	if len(s.chars) > 0 && !s.accessible {
+		// redraw the last frame shown before pausing
+		s.draw(s.chars[(s.frameIndex+len(s.chars)-1)%len(s.chars)])
+	}
+	s.flush()
+	s.emit(Event{Type: EventResume})
+}
+
+// Paused returns whether the spinner is paused.
+func (s *Spinner) Paused() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.paused
+}
+
+// Elapsed returns the time since the spinner was started, not counting
+// the time it was paused. It stops counting once the spinner is stopped.
+func (s *Spinner) Elapsed() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.startTime.IsZero() {
+		return 0
+	}
+	if !s.pausedAt.IsZero() {
+		return s.pausedAt.Sub(s.startTime)
+	}
+	return time.Since(s.startTime)
+}
+
+// Persist replaces the spinner line with a permanent status line for the
+// step that just completed, e.g. "✔ Fetched deps (1.2s)", and continues
+// spinning on the next line for the following step. The elapsed time is
+// measured from Start or the previous call to Persist, and left out of the
+// first status line when the spinner was never started. The line is
+// replaced in a single write while the animation is held, so no frame is
+// drawn in between. If the spinner isn't active or is paused only the
+// status line is written. In accessible mode the outcome is announced
+// instead, e.g. "Finished: Fetched deps, 1 second".
+func (s *Spinner) Persist(st Status, msg string) {
+	defer s.dispatch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var elapsed time.Duration
+	timed := !s.stepStart.IsZero()
+	line := fmt.Sprintf("%s %s\n", st, msg)
+	if timed {
+		elapsed = time.Since(s.stepStart)
+		line = statusLine(st, msg, nil, elapsed)
+	}
+	s.stepStart = time.Now()
+	s.emit(Event{Type: EventStatus, Elapsed: elapsed, Message: msg, Status: st})
+	if s.accessible {
+		if timed {
+			s.announceStatus(st, msg, nil, elapsed)
+		} else {
+			s.announce(statusLabels[st], msg)
+		}
+		s.flush()
+		return
+	}
+	if !s.active.get() || s.paused {
+		s.buf.WriteString(line)
+		s.flush()
+		return
+	}
+
+	s.erase()
+	s.buf.WriteString(line)
+	frame := s.lastFrame
+	if frame == "" && len(s.chars) > 0 {
+		frame = s.chars[0]
+	}
+	s.draw(frame)
+	s.flush()
 }
 
 // Restart will stop and start the indicator.
 func (s *Spinner) Restart() {
 	s.Stop()
 	s.Start()
+	s.mu.Lock()
+	s.emit(Event{Type: EventRestart})
+	s.mu.Unlock()
+	s.dispatch()
 }
 
 // Reverse will reverse the order of the slice assigned to the indicator.
+// The character set given to New or UpdateCharSet isn't modified.
 func (s *Spinner) Reverse() {
 	s.mu.Lock()
-	for i, j := 0, len(s.chars)-1; i < j; i, j = i+1, j-1 {
-		s.chars[i], s.chars[j] = s.chars[j], s.chars[i]
+	s.chars = Reversed(s.chars)
+	timing := make([]float64, len(s.timing))
+	for i, t := range s.timing {
+		timing[len(timing)-1-i] = t
 	}
+	s.timing = timing
 	s.mu.Unlock()
 }
 
 // Color will set the struct field for the given color to be used. The spinner
 // will need to be explicitly restarted.
 func (s *Spinner) Color(colors ...string) error {
+	fn, err := colorFunc(colors)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.color = fn
+	s.mu.Unlock()
+	return nil
+}
+
+// colorFunc returns the function coloring frames with the given colors.
+func colorFunc(colors []string) (func(a ...interface{}) string, error) {
 	colorAttributes := make([]color.Attribute, len(colors))
 
 	// Verify colours are valid and place the appropriate attribute in the array
 	for index, c := range colors {
 		if !validColor(c) {
-			return errInvalidColor
+			return nil, ErrInvalidColor
 		}
 		colorAttributes[index] = colorAttributeMap[c]
 	}
 
-	s.mu.Lock()
-	s.color = color.New(colorAttributes...).SprintFunc()
-	s.mu.Unlock()
-	return nil
+	return color.New(colorAttributes...).SprintFunc(), nil
 }
 
-// UpdateSpeed will set the indicator delay to the given value.
+// UpdateSpeed will set the indicator delay to the given value. Frames
+// with their own timing are scaled proportionally, see WithTiming. Frames
+// are shown for at least MinDelay.
 func (s *Spinner) UpdateSpeed(d time.Duration) {
 	s.mu.Lock()
 	s.Delay = d
 	s.mu.Unlock()
 }
 
-// UpdateCharSet will change the current character set to the given one.
+// UpdateCharSet will change the current character set to the given one, or
+// the fallback if it can't be rendered, see WithFallback.
 func (s *Spinner) UpdateCharSet(cs []string) {
 	s.mu.Lock()
+	cs = s.chooseCharSet(cs)
 	s.chars = cs
+	if s.autoPad {
+		s.chars = Pad(cs)
+	}
+	s.timing = charSetTiming(cs)
+	s.emit(Event{Type: EventCharSet, CharSet: s.chars})
 	s.mu.Unlock()
+	s.dispatch()
 }
 
-// erase deletes written characters on the current line.
+// erase deletes written characters on the current line. The escape
+// sequences are added to s.buf and written by the next flush.
 // Caller must already hold s.lock.
 func (s *Spinner) erase() {
+	if s.accessible {
+		// nothing is drawn in accessible mode
+		return
+	}
 	n := utf8.RuneCountInString(s.lastOutputPlain)
 	if runtime.GOOS == "windows" && !isWindowsTerminalOnWindows {
-		clearString := "\r" + strings.Repeat(" ", n) + "\r"
-		fmt.Fprint(s.Writer, clearString)
+		s.buf.WriteString("\r" + strings.Repeat(" ", n) + "\r")
 		s.lastOutputPlain = ""
 		return
 	}
@@ -468,24 +951,108 @@ func (s *Spinner) erase() {
 	// of the line. If n is 2, clear entire line. Cursor position does not
 	// change.
 	// \033[F - Go to the beginning of previous line
-	eraseCodeString := strings.Builder{}
 	// current position is at the end of the last printed line. Start by erasing current line
-	eraseCodeString.WriteString("\r\033[K") // start by erasing current line
+	s.buf.WriteString("\r\033[K") // start by erasing current line
 	for i := 1; i < numberOfLinesToErase; i++ {
 		// For each additional lines, go up one line and erase it.
-		eraseCodeString.WriteString("\033[F\033[K")
+		s.buf.WriteString("\033[F\033[K")
 	}
-	fmt.Fprint(s.Writer, eraseCodeString.String())
 	s.lastOutputPlain = ""
 }
 
+// Escape sequences enabling and disabling synchronized output, DEC private
+// mode 2026. Supporting terminals hold off rendering until the end sequence
+// is received so a frame appears at once. Other terminals ignore them.
+const (
+	beginSynchronizedUpdate = "\033[?2026h"
+	endSynchronizedUpdate   = "\033[?2026l"
+)
+
+// flush writes everything assembled in s.buf with a single call to Write
+// and resets the buffer for reuse. The content is only wrapped for
+// synchronized output when written to a terminal.
+// Caller must already hold s.lock.
+func (s *Spinner) flush() {
+	if s.buf.Len() == 0 {
+		return
+	}
+	defer s.buf.Reset()
+
+	if !s.SyncOutput || (isWindows && !isWindowsTerminalOnWindows) || !isRunningInTerminal(s) {
+		s.write(s.buf.Bytes())
+		return
+	}
+
+	// wrap the content in the synchronized update sequences, reusing the
+	// spare capacity at the end of the buffer
+	n := s.buf.Len()
+	s.buf.WriteString(beginSynchronizedUpdate)
+	s.buf.Write(s.buf.Bytes()[:n])
+	s.buf.WriteString(endSynchronizedUpdate)
+	s.write(s.buf.Bytes()[n:])
+}
+
+// write writes p to the Writer. A failure is recorded for Err and
+// reported to the listeners. If the Writer is closed, e.g. a pipe whose
+// reader exited, the spinner stops itself without writing anything more.
+// Caller must already hold s.lock.
+func (s *Spinner) write(p []byte) {
+	_, err := s.Writer.Write(p)
+	if err == nil {
+		return
+	}
+	s.err = err
+	s.emit(Event{Type: EventWriteError, Err: err})
+
+	if s.active.get() && isClosedWriterError(err) {
+		s.active.set(false)
+		s.paused = false
+		s.prompting = false
+		s.pausedAt = time.Now()
+		s.lastOutputPlain = ""
+		s.emit(Event{Type: EventStop, Elapsed: s.pausedAt.Sub(s.startTime), Err: err})
+		close(s.stopChan)
+	}
+}
+
+// isClosedWriterError returns whether err means nothing can be written
+// anymore.
+func isClosedWriterError(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) || errors.Is(err, io.ErrClosedPipe)
+}
+
+// Err returns the last error returned by the Writer since the spinner was
+// started, if any.
+func (s *Spinner) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+// supportsSynchronizedOutput reports whether the terminal is known to
+// support synchronized output, based on the environment it sets.
+func supportsSynchronizedOutput() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "WezTerm", "iTerm.app", "ghostty", "contour":
+		return true
+	}
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("ALACRITTY_WINDOW_ID") != "" {
+		return true
+	}
+	termName := os.Getenv("TERM")
+	return strings.HasPrefix(termName, "foot") || strings.HasPrefix(termName, "xterm-kitty")
+}
+
 // Lock allows for manual control to lock the spinner.
 func (s *Spinner) Lock() {
 	s.mu.Lock()
 }
 
-// Unlock allows for manual control to unlock the spinner.
+// Unlock allows for manual control to unlock the spinner. Anything
+// written to the terminal while the spinner was locked is overwritten
+// by the next frame, which redraws the whole line.
 func (s *Spinner) Unlock() {
+	s.redraw = true
 	s.mu.Unlock()
 }
 
@@ -499,8 +1066,9 @@ func GenerateNumberSequence(length int) []string {
 	return numSeq
 }
 
-// isRunningInTerminal check if the writer file descriptor is a terminal
-func isRunningInTerminal(s *Spinner) bool {
+// isRunningInTerminal check if the writer file descriptor is a terminal.
+// It is a variable so tests can run the spinner against a buffer.
+var isRunningInTerminal = func(s *Spinner) bool {
 	fd := s.WriterFile.Fd()
 	return term.IsTerminal(int(fd))
 }
@@ -16,6 +16,7 @@ package spinner
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -46,11 +47,98 @@ func (b *syncBuffer) Write(data []byte) (int, error) {
 // withOutput
 func withOutput(a []string, d time.Duration) (*Spinner, *syncBuffer) {
 	var out syncBuffer
-	s := New(a, d)
+	s := New(a, d, WithSyncOutput(false))
 	s.Writer = &out
 	return s, &out
 }
 
+// withTerminal makes spinners behave as if they were writing to a
+// terminal until the test completes
+func withTerminal(t *testing.T) {
+	isTerminal := isRunningInTerminal
+	isRunningInTerminal = func(*Spinner) bool { return true }
+	t.Cleanup(func() { isRunningInTerminal = isTerminal })
+}
+
+// TestNewWithOptions verifies that the spinner is configured from the
+// options
+func TestNewWithOptions(t *testing.T) {
+	var out syncBuffer
+	syncOutput, hideCursor := true, false
+	s, err := NewWithOptions(Options{
+		CharSetName: "line",
+		Delay:       50 * time.Millisecond,
+		Color:       "red",
+		Colors:      []string{"bold"},
+		Prefix:      "> ",
+		Suffix:      " working",
+		FinalMSG:    "done\n",
+		HideCursor:  &hideCursor,
+		SyncOutput:  &syncOutput,
+		Writer:      &out,
+		PostUpdate:  func(*Spinner) {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.chars, CharSets[9]) || s.Delay != 50*time.Millisecond {
+		t.Errorf("got character set %q and delay %s", s.chars, s.Delay)
+	}
+	if s.Prefix != "> " || s.Suffix != " working" || s.FinalMSG != "done\n" || s.HideCursor {
+		t.Errorf("got prefix %q, suffix %q, final message %q", s.Prefix, s.Suffix, s.FinalMSG)
+	}
+	if s.Writer != &out || s.PostUpdate == nil {
+		t.Error("writer or hook not set")
+	}
+	if !s.SyncOutput {
+		t.Error("synchronized output not enabled")
+	}
+
+	s, err = NewWithOptions(Options{CharSetName: "dots", Delay: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := New(CharSets[14], time.Second); s.HideCursor != want.HideCursor || s.SyncOutput != want.SyncOutput {
+		t.Errorf("got hidden cursor %t and synchronized output %t, want the defaults of New", s.HideCursor, s.SyncOutput)
+	}
+}
+
+// TestNewWithOptionsErrors verifies that invalid options are reported
+func TestNewWithOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		o    Options
+		err  error
+	}{
+		{"no character set", Options{Delay: time.Second}, ErrNoFrames},
+		{"empty character set", Options{CharSet: []string{}, Delay: time.Second}, ErrNoFrames},
+		{"empty fallback", Options{CharSet: CharSets[9], Fallback: []string{}, Delay: time.Second}, ErrNoFrames},
+		{"unknown character set", Options{CharSetName: "nope", Delay: time.Second}, ErrUnknownCharSet},
+		{"zero delay", Options{CharSet: CharSets[9]}, ErrInvalidDelay},
+		{"negative delay", Options{CharSet: CharSets[9], Delay: -time.Second}, ErrInvalidDelay},
+		{"invalid color", Options{CharSet: CharSets[9], Delay: time.Second, Color: "nope"}, ErrInvalidColor},
+		{"invalid attribute", Options{CharSet: CharSets[9], Delay: time.Second, Colors: []string{"nope"}}, ErrInvalidColor},
+	}
+
+	for _, tt := range tests {
+		s, err := NewWithOptions(tt.o)
+		if !errors.Is(err, tt.err) || s != nil {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// TestWithColorError verifies that an invalid color is reported by
+// ConfigErr
+func TestWithColorError(t *testing.T) {
+	if err := New(CharSets[9], time.Second, WithColor("cyan")).ConfigErr(); err != nil {
+		t.Errorf("got %v for a valid color", err)
+	}
+	if err := New(CharSets[9], time.Second, WithColor("nope")).ConfigErr(); !errors.Is(err, ErrInvalidColor) {
+		t.Errorf("got %v, want %v", err, ErrInvalidColor)
+	}
+}
+
 // TestNew verifies that the returned instance is of the proper type
 func TestNew(t *testing.T) {
 	for i := 0; i < len(CharSets); i++ {
@@ -155,6 +243,30 @@ func TestDisable(t *testing.T) {
 	}
 }
 
+// TestStateWhileLocked verifies that the state can be read while the
+// spinner is held with Lock, as before the getters were synchronized
+func TestStateWhileLocked(t *testing.T) {
+	withTerminal(t)
+	s, _ := withOutput(CharSets[4], 5*time.Millisecond)
+	s.Start()
+	defer s.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Lock()
+		defer s.Unlock()
+		if !s.Active() || !s.Enabled() {
+			t.Error("expected an active and enabled spinner")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reading the state while locked deadlocked")
+	}
+}
+
 // TestHookFunctions will verify that hook functions works as expected
 func TestHookFunctions(t *testing.T) {
 	if fd := os.Stdout.Fd(); !term.IsTerminal(int(fd)) {
@@ -189,6 +301,88 @@ func TestHookFunctions(t *testing.T) {
 	s = nil
 }
 
+// TestHooksCallSpinnerMethods verifies that the hooks may call the methods
+// of the spinner without deadlocking
+func TestHooksCallSpinnerMethods(t *testing.T) {
+	withTerminal(t)
+	tests := []struct {
+		name string
+		set  func(s *Spinner, call func(func()))
+	}{
+		{"PreUpdate UpdateCharSet", func(s *Spinner, call func(func())) {
+			s.PreUpdate = func(s *Spinner) { call(func() { s.UpdateCharSet(CharSets[9]) }) }
+		}},
+		{"PreUpdate Stop", func(s *Spinner, call func(func())) {
+			s.PreUpdate = func(s *Spinner) { call(s.Stop) }
+		}},
+		{"PostUpdate Color", func(s *Spinner, call func(func())) {
+			s.PostUpdate = func(s *Spinner) { call(func() { s.Color("red") }) }
+		}},
+		{"PostUpdate Stop", func(s *Spinner, call func(func())) {
+			s.PostUpdate = func(s *Spinner) { call(s.Stop) }
+		}},
+		{"PreRender Lock", func(s *Spinner, call func(func())) {
+			s.PreRender = func(*FrameContext) { call(func() { s.Lock(); s.Unlock() }) }
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := withOutput(CharSets[4], 10*time.Millisecond)
+			done := make(chan struct{})
+			var once sync.Once
+			tt.set(s, func(fn func()) {
+				once.Do(func() {
+					fn()
+					close(done)
+				})
+			})
+
+			s.Start()
+			defer s.Stop()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("hook deadlocked calling a method of the spinner")
+			}
+		})
+	}
+}
+
+// TestPreRenderChangesFrame verifies that the changes made by PreRender to
+// the frame context are drawn and kept
+func TestPreRenderChangesFrame(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput(CharSets[4], 10*time.Millisecond)
+	drawn := make(chan struct{}, 1)
+	s.PreRender = func(f *FrameContext) {
+		f.Frame = "X"
+		f.Suffix = " changed"
+		f.Delay = 20 * time.Millisecond
+		f.Color = []string{"red"}
+	}
+	s.PostUpdate = func(*Spinner) {
+		select {
+		case drawn <- struct{}{}:
+		default:
+		}
+	}
+
+	s.Start()
+	<-drawn
+	s.Stop()
+
+	if got := out.String(); !strings.Contains(got, "X changed") {
+		t.Errorf("output %q doesn't contain the changed frame", got)
+	}
+	if s.Suffix != " changed" {
+		t.Errorf("got suffix %q, want %q", s.Suffix, " changed")
+	}
+	if s.Delay != 20*time.Millisecond {
+		t.Errorf("got delay %s, want %s", s.Delay, 20*time.Millisecond)
+	}
+}
+
 // TestReverse will verify that the given spinner can stop and start again reversed
 func TestReverse(t *testing.T) {
 	a := New(CharSets[10], 1*time.Second)
@@ -267,7 +461,7 @@ func TestColorError(t *testing.T) {
 	const invalidColorName = "bluez"
 	const validColorName = "green"
 
-	if s.Color(invalidColorName) != errInvalidColor {
+	if s.Color(invalidColorName) != ErrInvalidColor {
 		t.Error("Color method did not return an error when given an invalid color.")
 	}
 
@@ -318,10 +512,253 @@ func TestComputeNumberOfLinesNeededToPrintStringInternal(t *testing.T) {
 	}
 }
 
+// TestDrawOnlyChangedCells verifies that unchanged lines are not rewritten
+// and that a frame change only rewrites the frame cells
+func TestDrawOnlyChangedCells(t *testing.T) {
+	s, out := withOutput([]string{"a", "b"}, 100*time.Millisecond)
+	s.Prefix = "pre "
+	s.Suffix = " suffix"
+
+	s.draw("a")
+	s.flush()
+	if !strings.HasSuffix(out.String(), "\rpre "+s.color("a")+" suffix") {
+		t.Errorf("expected full line on first draw, got %q", out.String())
+	}
+
+	out.Reset()
+	s.draw("a")
+	s.flush()
+	if out.Len() != 0 {
+		t.Errorf("expected nothing written for an unchanged line, got %q", out.String())
+	}
+
+	out.Reset()
+	s.draw("b")
+	s.flush()
+	if want := "\r\033[4C" + s.color("b") + "\033[7C"; out.String() != want {
+		t.Errorf("expected only the frame to be rewritten. got=%q want=%q", out.String(), want)
+	}
+
+	out.Reset()
+	s.Suffix = " done"
+	s.draw("a")
+	s.flush()
+	if !strings.HasPrefix(out.String(), "\r\033[K") {
+		t.Errorf("expected the line to be erased when the suffix changes, got %q", out.String())
+	}
+	if s.lastOutputPlain != "\rpre a done" {
+		t.Errorf("unexpected last output %q", s.lastOutputPlain)
+	}
+}
+
+// TestUnlockRedrawsLine verifies that the whole line is redrawn after the
+// spinner was locked manually
+func TestUnlockRedrawsLine(t *testing.T) {
+	s, out := withOutput([]string{"a", "b"}, 100*time.Millisecond)
+	s.Suffix = " working"
+	s.draw("a")
+	s.flush()
+
+	s.Lock()
+	s.Unlock()
+	out.Reset()
+	s.draw("b")
+	s.flush()
+	if want := "\r\033[K\r" + s.color("b") + " working"; out.String() != want {
+		t.Errorf("expected the whole line to be redrawn. got=%q want=%q", out.String(), want)
+	}
+}
+
+// recordingWriter records every call to Write
+type recordingWriter struct {
+	writes []string
+}
+
+// Write
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.writes = append(w.writes, string(data))
+	return len(data), nil
+}
+
+// TestSingleWritePerUpdate verifies that erasing and drawing a frame
+// results in a single write, wrapped for synchronized output if enabled
+func TestSingleWritePerUpdate(t *testing.T) {
+	withTerminal(t)
+	var w recordingWriter
+	s := New([]string{"a", "bb"}, 100*time.Millisecond, WithWriter(&w), WithSyncOutput(false))
+	s.Suffix = " working"
+
+	s.draw("a")
+	s.flush()
+	s.draw("bb")
+	s.flush()
+	if len(w.writes) != 2 {
+		t.Fatalf("expected one write per frame, got %q", w.writes)
+	}
+	if want := "\r\033[K\r" + s.color("bb") + " working"; w.writes[1] != want {
+		t.Errorf("expected erase and frame in one write. got=%q want=%q", w.writes[1], want)
+	}
+
+	s.flush()
+	if len(w.writes) != 2 {
+		t.Errorf("expected no write for an empty buffer, got %q", w.writes)
+	}
+
+	s.SyncOutput = true
+	s.Suffix = " done"
+	s.draw("a")
+	s.flush()
+	if len(w.writes) != 3 {
+		t.Fatalf("expected one write per frame, got %q", w.writes)
+	}
+	want := beginSynchronizedUpdate + "\r\033[K\r" + s.color("a") + " done" + endSynchronizedUpdate
+	if w.writes[2] != want {
+		t.Errorf("expected synchronized update. got=%q want=%q", w.writes[2], want)
+	}
+}
+
+// TestSyncOutputNotTerminal verifies that output isn't wrapped for
+// synchronized output when the Writer isn't a terminal
+func TestSyncOutputNotTerminal(t *testing.T) {
+	var out syncBuffer
+	s := New([]string{"a"}, time.Hour, WithWriter(&out), WithSyncOutput(true))
+
+	s.Persist(Success, "Fetched deps")
+	if want := Success.String() + " Fetched deps\n"; out.String() != want {
+		t.Errorf("unexpected output. got=%q want=%q", out.String(), want)
+	}
+}
+
+// TestLookupCharSet verifies that character sets can be found by name and index
+func TestLookupCharSet(t *testing.T) {
+	for _, name := range CharSetNames() {
+		if _, err := LookupCharSet(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	cs, err := LookupCharSet("9")
+	if err != nil || !reflect.DeepEqual(cs, CharSets[9]) {
+		t.Errorf("expected character set 9, got %q, %v", cs, err)
+	}
+	for _, name := range []string{"nope", "-1", "1000", ""} {
+		if _, err := LookupCharSet(name); err != ErrUnknownCharSet {
+			t.Errorf("%q: expected unknown character set error, got %v", name, err)
+		}
+	}
+}
+
+// TestPersist verifies that the spinner line is replaced by a status line
+// and the spinner continues on the next line
+func TestPersist(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput([]string{"a"}, time.Hour)
+	s.Suffix = " Fetching deps"
+	s.Start()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	out.Reset()
+	s.Suffix = " Compiling"
+	s.mu.Unlock()
+	s.Persist(Success, "Fetched deps")
+	result := elapsedPattern.ReplaceAllString(out.String(), "(0s)")
+	if want := "\r\033[K" + Success.String() + " Fetched deps (0s)\n\r\033[K\r" + s.color("a") + " Compiling"; result != want {
+		t.Errorf("unexpected output. got=%q want=%q", result, want)
+	}
+}
+
+// TestPersistBeforeStart verifies that no elapsed time is reported for a
+// step that didn't start with the spinner
+func TestPersistBeforeStart(t *testing.T) {
+	s, out := withOutput([]string{"a"}, time.Hour)
+
+	s.Persist(Success, "Checked config")
+	if want := Success.String() + " Checked config\n"; out.String() != want {
+		t.Errorf("unexpected output. got=%q want=%q", out.String(), want)
+	}
+
+	out.Reset()
+	s.Persist(Success, "Fetched deps")
+	if result := elapsedPattern.ReplaceAllString(out.String(), "(0s)"); result != Success.String()+" Fetched deps (0s)\n" {
+		t.Errorf("expected the time since the previous step, got %q", out.String())
+	}
+}
+
+// TestPauseResume verifies that a paused spinner doesn't draw and keeps
+// its frame and elapsed time
+func TestPauseResume(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput([]string{"a", "b", "c"}, 5*time.Millisecond)
+	s.Suffix = " working"
+	s.Start()
+	defer s.Stop()
+	time.Sleep(12 * time.Millisecond)
+
+	s.Pause()
+	if !s.Paused() {
+		t.Error("expected spinner to be paused")
+	}
+	s.mu.Lock()
+	frame := s.lastFrame
+	out.Reset()
+	s.mu.Unlock()
+	elapsed := s.Elapsed()
+	time.Sleep(30 * time.Millisecond)
+
+	out.Lock()
+	if out.Len() != 0 {
+		t.Errorf("expected nothing written while paused, got %q", out.String())
+	}
+	out.Unlock()
+	if s.Elapsed() != elapsed {
+		t.Errorf("expected elapsed time to be held while paused, got %v != %v", s.Elapsed(), elapsed)
+	}
+
+	s.Resume()
+	if s.Paused() {
+		t.Error("expected spinner to be resumed")
+	}
+	out.Lock()
+	result := out.String()
+	out.Unlock()
+	if want := "\033[?25l\r\033[K\r" + s.color(frame) + " working"; !strings.HasPrefix(result, want) {
+		t.Errorf("expected the line to be redrawn from the paused frame. got=%q want=%q", result, want)
+	}
+	if d := s.Elapsed() - elapsed; d < 0 || d > 20*time.Millisecond {
+		t.Errorf("expected time spent paused to be excluded, got %v more", d)
+	}
+}
+
 /*
 Benchmarks
 */
 
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int
+}
+
+// Write
+func (w *countingWriter) Write(data []byte) (int, error) {
+	w.n += len(data)
+	return len(data), nil
+}
+
+// BenchmarkDraw reports the number of bytes written per frame
+func BenchmarkDraw(b *testing.B) {
+	var w countingWriter
+	s := New(CharSets[14], 100*time.Millisecond, WithWriter(&w))
+	s.Prefix = "Deploying: "
+	s.Suffix = " " + strings.Repeat("waiting for the cluster ", 3)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s.draw(s.chars[n%len(s.chars)])
+		s.flush()
+	}
+	b.ReportMetric(float64(w.n)/float64(b.N), "bytes/frame")
+}
+
 // BenchmarkNew runs a benchmark for the New() function
 func BenchmarkNew(b *testing.B) {
 	for n := 0; n < b.N; n++ {
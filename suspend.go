@@ -0,0 +1,98 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"bytes"
+	"io"
+)
+
+// Suspend pauses the spinner, calls fn and resumes the spinner once fn
+// returns, e.g. to prompt the user for input. The cursor is visible while
+// fn runs. The error returned by fn is returned unchanged. If the spinner
+// is already paused it is left paused.
+func (s *Spinner) Suspend(fn func() error) error {
+	if s.suspend(false) {
+		defer s.Resume()
+	}
+	return fn()
+}
+
+// suspend pauses the spinner if it is running and returns whether it did.
+// prompt records that the pause is held until a prompt is answered, see
+// NewSuspendReader.
+func (s *Spinner) suspend(prompt bool) bool {
+	s.mu.RLock()
+	running := s.active.get() && !s.paused
+	s.mu.RUnlock()
+	if !running {
+		return false
+	}
+	s.Pause()
+	s.mu.Lock()
+	s.prompting = prompt
+	s.mu.Unlock()
+	return true
+}
+
+// suspendWriter is the io.Writer returned by NewSuspendWriter.
+type suspendWriter struct {
+	w io.Writer
+	s *Spinner
+}
+
+// NewSuspendWriter returns an io.Writer writing to w, typically
+// os.Stdout, that pauses the spinner before writing a prompt. The spinner
+// stays paused until the answer is read through a reader returned by
+// NewSuspendReader.
+func NewSuspendWriter(w io.Writer, s *Spinner) io.Writer {
+	return &suspendWriter{w: w, s: s}
+}
+
+// Write pauses the spinner and writes p.
+func (sw *suspendWriter) Write(p []byte) (int, error) {
+	sw.s.suspend(true)
+	return sw.w.Write(p)
+}
+
+// suspendReader is the io.Reader returned by NewSuspendReader.
+type suspendReader struct {
+	r io.Reader
+	s *Spinner
+}
+
+// NewSuspendReader returns an io.Reader reading from r, typically
+// os.Stdin, that pauses the spinner while waiting for input. The spinner
+// is resumed once a line has been read, or reading fails, if it was paused
+// by the reader or by a writer returned by NewSuspendWriter.
+func NewSuspendReader(r io.Reader, s *Spinner) io.Reader {
+	return &suspendReader{r: r, s: s}
+}
+
+// Read pauses the spinner and reads from the underlying reader.
+func (sr *suspendReader) Read(p []byte) (int, error) {
+	sr.s.suspend(true)
+	n, err := sr.r.Read(p)
+	if err != nil || bytes.IndexByte(p[:n], '\n') >= 0 {
+		sr.s.mu.Lock()
+		resume := sr.s.prompting
+		sr.s.prompting = false
+		sr.s.mu.Unlock()
+		if resume {
+			sr.s.Resume()
+		}
+	}
+	return n, err
+}
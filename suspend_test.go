@@ -0,0 +1,97 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSuspend verifies that the spinner is paused while fn runs
+func TestSuspend(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput(CharSets[9], 5*time.Millisecond)
+	s.Start()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	errPrompt := errors.New("no input")
+	err := s.Suspend(func() error {
+		if !s.Paused() {
+			t.Error("expected spinner to be paused")
+		}
+		out.Lock()
+		result := out.String()
+		out.Unlock()
+		if !strings.HasSuffix(result, "\r\033[K\033[?25h") {
+			t.Errorf("expected line erased and cursor shown, got %q", result)
+		}
+		return errPrompt
+	})
+	if err != errPrompt {
+		t.Errorf("expected the error to be returned unchanged, got %v", err)
+	}
+	if s.Paused() {
+		t.Error("expected spinner to be resumed")
+	}
+
+	s.Pause()
+	s.Suspend(func() error { return nil })
+	if !s.Paused() {
+		t.Error("expected a paused spinner to stay paused")
+	}
+}
+
+// TestSuspendReader verifies that the spinner is paused from the prompt
+// until its answer is read
+func TestSuspendReader(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput(CharSets[9], 5*time.Millisecond)
+	s.Start()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	pr, pw := io.Pipe()
+	stdin := bufio.NewReader(NewSuspendReader(pr, s))
+	stdout := NewSuspendWriter(out, s)
+
+	fmt.Fprint(stdout, "Name: ")
+	if !s.Paused() {
+		t.Error("expected spinner to be paused by the prompt")
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		pw.Write([]byte("Gopher\n"))
+	}()
+	answer, _ := stdin.ReadString('\n')
+	if answer != "Gopher\n" {
+		t.Errorf("unexpected answer %q", answer)
+	}
+	if s.Paused() {
+		t.Error("expected spinner to be resumed once answered")
+	}
+
+	out.Lock()
+	result := out.String()
+	out.Unlock()
+	if !strings.Contains(result, "\033[?25hName: \033[?25l") {
+		t.Errorf("expected nothing drawn between the prompt and its answer, got %q", result)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"math"
+	"time"
+)
+
+// MinDelay is the shortest time a frame is shown. Shorter delays,
+// including zero and negative ones, are raised to it so the spinner never
+// redraws in a busy loop.
+const MinDelay = 10 * time.Millisecond
+
+// CharSetTimings holds the duration of each frame of some of the character
+// sets in CharSets, as multiples of the delay of the spinner. Sets that
+// aren't listed show every frame for the delay.
+var CharSetTimings = map[int][]float64{
+	12: Hold(len(CharSets[12]), 3, 0, 4), // the fish pauses before turning around
+	52: Hold(len(CharSets[52]), 3, 4),    // the ball pauses at the right, its first and last frames already show it at the left
+}
+
+// Hold returns the timing of n frames showing the given frames for hold
+// times the delay and the others for the delay.
+func Hold(n int, hold float64, frames ...int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	timing := make([]float64, n)
+	for i := range timing {
+		timing[i] = 1
+	}
+	for _, i := range frames {
+		if i >= 0 && i < n {
+			timing[i] = hold
+		}
+	}
+	return timing
+}
+
+// HoldEnds returns the timing of n frames showing the first and last
+// frames for hold times the delay and the others for the delay.
+func HoldEnds(n int, hold float64) []float64 {
+	return Hold(n, hold, 0, n-1)
+}
+
+// EaseIn returns the timing of n frames starting slowly and speeding up.
+// The frames take n times the delay in total.
+func EaseIn(n int) []float64 {
+	return ease(n, func(p float64) float64 { return 1 + math.Cos(p*math.Pi/2) })
+}
+
+// EaseOut returns the timing of n frames starting quickly and slowing
+// down. The frames take n times the delay in total.
+func EaseOut(n int) []float64 {
+	return ease(n, func(p float64) float64 { return 1 + math.Sin(p*math.Pi/2) })
+}
+
+// EaseInOut returns the timing of n frames that are slow at both ends and
+// quick in the middle. The frames take n times the delay in total.
+func EaseInOut(n int) []float64 {
+	return ease(n, func(p float64) float64 { return 2 - math.Sin(p*math.Pi) })
+}
+
+// ease returns the timing of n frames, the frame at position p, from 0 to
+// 1, lasting in proportion to weight(p). The timing is scaled so that the
+// frames take n times the delay in total.
+func ease(n int, weight func(p float64) float64) []float64 {
+	if n <= 1 {
+		return Hold(n, 1)
+	}
+	timing := make([]float64, n)
+	var total float64
+	for i := range timing {
+		timing[i] = weight(float64(i) / float64(n-1))
+		total += timing[i]
+	}
+	for i := range timing {
+		timing[i] *= float64(n) / total
+	}
+	return timing
+}
+
+// WithTiming shows each frame for the given multiple of the delay, e.g. as
+// returned by EaseInOut or Hold. The timing is ignored unless it has a
+// positive value for each frame of the character set.
+func WithTiming(timing []float64) Option {
+	return func(s *Spinner) {
+		s.timing = timing
+	}
+}
+
+// charSetTiming returns the timing of the given character set if it's one
+// of the sets in CharSets with an entry in CharSetTimings.
+func charSetTiming(cs []string) []float64 {
+	if len(cs) == 0 {
+		return nil
+	}
+	for i, timing := range CharSetTimings {
+		if set := CharSets[i]; len(set) == len(cs) && &set[0] == &cs[0] {
+			return timing
+		}
+	}
+	return nil
+}
+
+// timed returns whether the frames are shown for the durations given by
+// s.timing. Caller must already hold s.lock.
+func (s *Spinner) timed() bool {
+	if len(s.timing) == 0 || len(s.timing) != len(s.chars) {
+		return false
+	}
+	for _, t := range s.timing {
+		if t <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// frameDelay returns how long the frame with the given index in the
+// character set is shown. Caller must already hold s.lock.
+func (s *Spinner) frameDelay(index int) time.Duration {
+	if !s.timed() {
+		return clampDelay(s.Delay)
+	}
+	return clampDelay(time.Duration(float64(s.Delay) * s.timing[index%len(s.timing)]))
+}
+
+// clampDelay returns d, or MinDelay if d is shorter.
+func clampDelay(d time.Duration) time.Duration {
+	if d < MinDelay {
+		return MinDelay
+	}
+	return d
+}
+
+// deadline returns the time since the start of the animation the frame
+// following the given one is due. Caller must already hold s.lock.
+func (s *Spinner) deadline(index int) time.Duration {
+	if !s.timed() {
+		return time.Duration(index+1) * clampDelay(s.Delay)
+	}
+	var cycle, d time.Duration
+	for i := range s.chars {
+		fd := s.frameDelay(i)
+		cycle += fd
+		if i <= index%len(s.chars) {
+			d += fd
+		}
+	}
+	return time.Duration(index/len(s.chars))*cycle + d
+}
+
+// frameAt returns the index, counted from the start of the animation, of
+// the frame due the given time after the start. Caller must already hold
+// s.lock.
+func (s *Spinner) frameAt(elapsed time.Duration) int {
+	if !s.timed() {
+		return int(elapsed / clampDelay(s.Delay))
+	}
+	cycle := s.deadline(len(s.chars) - 1)
+	index := int(elapsed/cycle) * len(s.chars)
+	elapsed %= cycle
+	for i := range s.chars {
+		if elapsed -= s.frameDelay(i); elapsed < 0 {
+			return index + i
+		}
+	}
+	return index
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestEasing verifies the shape and total duration of the easing timings
+func TestEasing(t *testing.T) {
+	tests := []struct {
+		name   string
+		timing []float64
+		slow   []int // frames slower than the middle one
+	}{
+		{"EaseIn", EaseIn(5), []int{0}},
+		{"EaseOut", EaseOut(5), []int{4}},
+		{"EaseInOut", EaseInOut(5), []int{0, 4}},
+	}
+
+	for _, tt := range tests {
+		var total float64
+		for _, d := range tt.timing {
+			total += d
+		}
+		if math.Abs(total-5) > 1e-9 {
+			t.Errorf("%s: frames take %f times the delay, want 5", tt.name, total)
+		}
+		for _, i := range tt.slow {
+			if tt.timing[i] <= tt.timing[2] {
+				t.Errorf("%s: frame %d isn't slower than the middle one: %v", tt.name, i, tt.timing)
+			}
+		}
+	}
+
+	if got := HoldEnds(4, 3); !reflect.DeepEqual(got, []float64{3, 1, 1, 3}) {
+		t.Errorf("HoldEnds: got %v", got)
+	}
+}
+
+// TestRenderTiming verifies that the deadlines of the frames follow their
+// timing and scale with the delay
+func TestRenderTiming(t *testing.T) {
+	s := New([]string{"a", "b", "c"}, 10*time.Millisecond, WithTiming([]float64{1, 3, 1}))
+
+	for i, want := range []time.Duration{10, 40, 50, 60, 90} {
+		if got := s.Render(i).Deadline; got != want*time.Millisecond {
+			t.Errorf("Render(%d): got deadline %s, want %s", i, got, want*time.Millisecond)
+		}
+	}
+	for elapsed, want := range map[time.Duration]string{0: "a", 10: "b", 39: "b", 40: "c", 55: "a", 65: "b"} {
+		if got := s.RenderAt(elapsed * time.Millisecond).Plain; got != want {
+			t.Errorf("RenderAt(%s): got %q, want %q", elapsed*time.Millisecond, got, want)
+		}
+	}
+
+	s.UpdateSpeed(20 * time.Millisecond)
+	if got := s.Render(1).Deadline; got != 80*time.Millisecond {
+		t.Errorf("got deadline %s after UpdateSpeed, want %s", got, 80*time.Millisecond)
+	}
+}
+
+// TestCharSetTimings verifies that the sets in CharSets are timed as given
+// in CharSetTimings, and that other sets aren't
+func TestCharSetTimings(t *testing.T) {
+	for i, timing := range CharSetTimings {
+		if len(timing) != len(CharSets[i]) {
+			t.Errorf("CharSetTimings[%d] has %d frames, want %d", i, len(timing), len(CharSets[i]))
+		}
+	}
+
+	s := New(CharSets[52], 10*time.Millisecond)
+	if got := s.Render(4).Deadline - s.Render(3).Deadline; got != 30*time.Millisecond {
+		t.Errorf("got frame duration %s, want %s", got, 30*time.Millisecond)
+	}
+
+	s.UpdateCharSet(append([]string{}, CharSets[52]...))
+	if got := s.Render(4).Deadline - s.Render(3).Deadline; got != 10*time.Millisecond {
+		t.Errorf("got frame duration %s for a copy of the set, want %s", got, 10*time.Millisecond)
+	}
+}
+
+// TestTimedFramesDrawn verifies that the spinner shows each frame for its
+// duration
+func TestTimedFramesDrawn(t *testing.T) {
+	withTerminal(t)
+	s, _ := withOutput([]string{"a", "b"}, 5*time.Millisecond)
+	WithTiming([]float64{1, 100})(s)
+	var frames int32
+	s.PostUpdate = func(*Spinner) { atomic.AddInt32(&frames, 1) }
+
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	s.Stop()
+
+	// with a 5ms delay for every frame about 20 frames would be drawn
+	if n := atomic.LoadInt32(&frames); n > 3 {
+		t.Errorf("drew %d frames, want at most 3", n)
+	}
+}
+
+// TestTimedBlockFrames verifies that task groups show each frame for its
+// duration
+func TestTimedBlockFrames(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput([]string{"a", "b"}, 5*time.Millisecond)
+	WithTiming([]float64{1, 100})(s)
+	g := NewTaskGroup(s)
+	g.Go("task", func() error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+	g.Wait()
+
+	// with a 5ms delay for every frame the first one would be drawn about
+	// 10 times
+	if n := strings.Count(out.String(), s.color("a")+" task"); n > 2 {
+		t.Errorf("drew the first frame %d times, want at most 2", n)
+	}
+}
+
+// TestMinDelay verifies that frames are shown for at least MinDelay,
+// whatever the delay and timing
+func TestMinDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		delay  time.Duration
+		timing []float64
+	}{
+		{"zero delay", 0, nil},
+		{"negative delay", -time.Second, nil},
+		{"short delay", time.Microsecond, nil},
+		{"short timing", 20 * time.Millisecond, []float64{0.01, 1}},
+	}
+
+	for _, tt := range tests {
+		s := New([]string{"a", "b"}, tt.delay, WithTiming(tt.timing))
+		for i := 0; i < 4; i++ {
+			if d := s.Render(i).Deadline - s.Render(i-1).Deadline; i > 0 && d < MinDelay {
+				t.Errorf("%s: frame %d shown for %s", tt.name, i, d)
+			}
+		}
+		if got := s.RenderAt(time.Hour).Index; got < 0 || got > 1 {
+			t.Errorf("%s: RenderAt returned frame %d", tt.name, got)
+		}
+	}
+}
+
+// TestZeroDelayDrawsBoundedFrames verifies that a spinner with a zero
+// delay draws at most a frame every MinDelay
+func TestZeroDelayDrawsBoundedFrames(t *testing.T) {
+	withTerminal(t)
+	s, _ := withOutput([]string{"a", "b"}, 0)
+	var frames int32
+	s.PostUpdate = func(*Spinner) { atomic.AddInt32(&frames, 1) }
+
+	s.Start()
+	time.Sleep(100 * time.Millisecond)
+	s.Stop()
+
+	if n, max := atomic.LoadInt32(&frames), int32(100*time.Millisecond/MinDelay)+2; n > max {
+		t.Errorf("drew %d frames, want at most %d", n, max)
+	}
+}
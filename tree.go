@@ -0,0 +1,206 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tree shows nested steps, each with its own spinner or status, child
+// steps indented beneath their parent. The lines are drawn with the
+// character set, color, delay and writer of the spinner given to NewTree.
+type Tree struct {
+	mu       sync.Mutex
+	s        *Spinner
+	block    *block
+	steps    []*Step
+	Collapse bool // Collapse hides the children of completed steps unless one failed
+}
+
+// Step is a step of a Tree. It is running from the time it's created
+// until Done or Skip is called.
+type Step struct {
+	tree      *Tree
+	name      string
+	prefix    string
+	suffix    string
+	children  []*Step
+	done      bool
+	status    Status
+	err       error
+	start     time.Time
+	elapsed   time.Duration
+	started   bool // started is set once the start is announced
+	announced bool // announced is set once the outcome is announced
+}
+
+// NewTree provides a pointer to an instance of Tree drawing its lines
+// with the given spinner. The spinner itself must not be started.
+func NewTree(s *Spinner) *Tree {
+	t := &Tree{s: s, Collapse: true}
+	t.block = newBlock(s, t.render, t.announce)
+	return t
+}
+
+// Step adds a top level step with the given name. The first call starts
+// drawing the tree.
+func (t *Tree) Step(name string) *Step {
+	st := &Step{tree: t, name: name, start: time.Now()}
+	t.mu.Lock()
+	t.steps = append(t.steps, st)
+	t.mu.Unlock()
+	t.block.start()
+	return st
+}
+
+// Stop stops drawing the tree and leaves its final state on screen. In
+// accessible mode, where the outcome of each step is announced instead,
+// nothing more is left.
+func (t *Tree) Stop() {
+	t.s.mu.Lock()
+	final := t.render("")
+	if t.s.accessible {
+		final = ""
+	}
+	t.s.mu.Unlock()
+	t.block.finish(final)
+}
+
+// Step adds a child step with the given name.
+func (st *Step) Step(name string) *Step {
+	child := &Step{tree: st.tree, name: name, start: time.Now()}
+	st.tree.mu.Lock()
+	st.children = append(st.children, child)
+	st.tree.mu.Unlock()
+	return child
+}
+
+// SetPrefix sets the text shown before the spinner or status of the step.
+func (st *Step) SetPrefix(prefix string) {
+	st.tree.mu.Lock()
+	st.prefix = prefix
+	st.tree.mu.Unlock()
+}
+
+// SetSuffix sets the text shown after the name of the step.
+func (st *Step) SetSuffix(suffix string) {
+	st.tree.mu.Lock()
+	st.suffix = suffix
+	st.tree.mu.Unlock()
+}
+
+// Done completes the step, as failed if err isn't nil.
+func (st *Step) Done(err error) {
+	status := Success
+	if err != nil {
+		status = Failure
+	}
+	st.finish(status, err)
+}
+
+// Skip completes the step as skipped.
+func (st *Step) Skip() {
+	st.finish(Skipped, nil)
+}
+
+// finish completes the step with the given status.
+func (st *Step) finish(status Status, err error) {
+	st.tree.mu.Lock()
+	defer st.tree.mu.Unlock()
+	if st.done {
+		return
+	}
+	st.done, st.status, st.err = true, status, err
+	st.elapsed = time.Since(st.start)
+}
+
+// failed returns whether the step or one of its descendants failed.
+// Caller must already hold st.tree.mu.
+func (st *Step) failed() bool {
+	if st.done && st.status == Failure {
+		return true
+	}
+	for _, child := range st.children {
+		if child.failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// render returns the lines of the steps, using frame for running steps.
+// Caller must already hold t.s.lock.
+func (t *Tree) render(frame string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var b strings.Builder
+	for _, st := range t.steps {
+		t.renderStep(&b, st, 0, frame)
+	}
+	return b.String()
+}
+
+// renderStep writes the line of the step and those of its children.
+// Caller must already hold t.mu.
+func (t *Tree) renderStep(b *strings.Builder, st *Step, depth int, frame string) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(st.prefix)
+	if st.done {
+		b.WriteString(statusLine(st.status, st.name+st.suffix, st.err, st.elapsed))
+	} else {
+		if frame == "" {
+			frame = " "
+		}
+		b.WriteString(frame + " " + st.name + st.suffix + "\n")
+	}
+
+	if st.done && t.Collapse && !st.failed() {
+		return
+	}
+	for _, child := range st.children {
+		t.renderStep(b, child, depth+1, frame)
+	}
+}
+
+// announce announces the steps that started or completed since the
+// previous call, in accessible mode. Caller must already hold t.s.lock.
+func (t *Tree) announce() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, st := range t.steps {
+		t.announceStep(st)
+	}
+}
+
+// announceStep announces the changes of the step and its children, the
+// outcome of the step following those of its children.
+// Caller must already hold t.s.lock and t.mu.
+func (t *Tree) announceStep(st *Step) {
+	if !st.done && !st.started {
+		t.s.announce("Started", st.name+st.suffix)
+		st.started = true
+	}
+	for _, child := range st.children {
+		t.announceStep(child)
+	}
+	if st.done && !st.announced {
+		t.s.announceStatus(st.status, st.name+st.suffix, st.err, st.elapsed)
+		st.started, st.announced = true, true
+	}
+}
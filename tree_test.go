@@ -0,0 +1,102 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// elapsedPattern matches the elapsed time of status lines
+var elapsedPattern = regexp.MustCompile(`\([0-9.]+[mµn]?s\)`)
+
+// TestTree verifies nesting, collapsing and the final state of the steps
+func TestTree(t *testing.T) {
+	s, out := withOutput(CharSets[9], 10*time.Millisecond)
+	tree := NewTree(s)
+
+	deploy := tree.Step("Deploy")
+	build := deploy.Step("Build")
+	build.Step("Compile").Done(nil)
+	build.Done(nil)
+	push := deploy.Step("Push")
+	push.SetSuffix(" to registry")
+	push.Step("Upload layers").Done(errors.New("timeout"))
+	push.Done(errors.New("upload failed"))
+	deploy.Step("Notify").Skip()
+	deploy.Done(errors.New("push failed"))
+
+	verify := tree.Step("Verify")
+	verify.SetPrefix("> ")
+	verify.Step("Smoke tests").Done(nil)
+	verify.Done(nil)
+	tree.Step("Cleanup")
+	tree.Stop()
+
+	expected := strings.Join([]string{
+		Failure.String() + " Deploy: push failed (0s)",
+		"  " + Success.String() + " Build (0s)",
+		"  " + Failure.String() + " Push to registry: upload failed (0s)",
+		"    " + Failure.String() + " Upload layers: timeout (0s)",
+		"  " + Skipped.String() + " Notify (0s)",
+		"> " + Success.String() + " Verify (0s)",
+		"  Cleanup",
+		"",
+	}, "\n")
+	if got := elapsedPattern.ReplaceAllString(out.String(), "(0s)"); got != expected {
+		t.Errorf("unexpected tree.\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+// TestTreeAnimated verifies that running steps are drawn with the frames
+func TestTreeAnimated(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput([]string{"*"}, 5*time.Millisecond)
+	tree := NewTree(s)
+	tree.Collapse = false
+
+	parent := tree.Step("Parent")
+	parent.Step("Child")
+	time.Sleep(20 * time.Millisecond)
+	parent.Done(nil)
+	tree.Stop()
+
+	result := out.String()
+	if !strings.Contains(result, s.color("*")+" Parent\n  "+s.color("*")+" Child\n") {
+		t.Errorf("expected running steps with their frame, got %q", result)
+	}
+	if !strings.HasSuffix(result, "  Child\n") {
+		t.Errorf("expected children of completed steps when not collapsing, got %q", result)
+	}
+}
+
+// TestTreeRestart verifies that steps can be added again after Stop
+func TestTreeRestart(t *testing.T) {
+	withTerminal(t)
+	s, out := withOutput([]string{"*"}, 5*time.Millisecond)
+	tree := NewTree(s)
+
+	for _, name := range []string{"First", "Second"} {
+		tree.Step(name).Done(nil)
+		time.Sleep(10 * time.Millisecond)
+		tree.Stop()
+	}
+	if !strings.Contains(out.String(), "Second") {
+		t.Errorf("expected the second step to be drawn, got %q", out.String())
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Problems reported by Validate
+var (
+	ErrNoFrames         = errors.New("no frames")
+	ErrEmptyFrame       = errors.New("empty frame")
+	ErrFrameWidth       = errors.New("frame narrower than the widest frame")
+	ErrControlCharacter = errors.New("frame contains a control character")
+)
+
+// FrameError reports a problem with a frame of a character set.
+type FrameError struct {
+	Index int    // Index of the frame in the character set
+	Frame string // Frame with the problem
+	Err   error  // Err is the problem, e.g. ErrEmptyFrame
+}
+
+// Error returns the problem along with the frame.
+func (e *FrameError) Error() string {
+	return fmt.Sprintf("frame %d %q: %v", e.Index, e.Frame, e.Err)
+}
+
+// Unwrap returns the problem, so it can be checked with errors.Is.
+func (e *FrameError) Unwrap() error {
+	return e.Err
+}
+
+// FrameErrors holds every problem found by Validate.
+type FrameErrors []*FrameError
+
+// Error returns the problems separated by semicolons.
+func (e FrameErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks that the given frames can be animated without the text
+// after the spinner moving or the line being garbled. It returns
+// ErrNoFrames if there are none, or FrameErrors reporting every empty
+// frame, frame narrower than the widest one and frame containing a
+// control character, such as an escape sequence. Frames of differing
+// widths can be fixed with Pad or WithAutoPad.
+func Validate(frames []string) error {
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+	width := 0
+	for _, f := range frames {
+		if w := displayWidth(f); w > width {
+			width = w
+		}
+	}
+
+	var errs FrameErrors
+	for i, f := range frames {
+		switch {
+		case f == "":
+			errs = append(errs, &FrameError{Index: i, Frame: f, Err: ErrEmptyFrame})
+		case strings.IndexFunc(f, unicode.IsControl) >= 0:
+			errs = append(errs, &FrameError{Index: i, Frame: f, Err: ErrControlCharacter})
+		case displayWidth(f) != width:
+			errs = append(errs, &FrameError{Index: i, Frame: f, Err: ErrFrameWidth})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// WithAutoPad pads the frames of the character set, including those given
+// to UpdateCharSet, with spaces to the width of the widest frame, see Pad.
+func WithAutoPad() Option {
+	return func(s *Spinner) {
+		s.autoPad = true
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestValidate verifies the problems reported by Validate
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []string
+		want   []error
+	}{
+		{"valid", []string{"a", "b", "c"}, nil},
+		{"no frames", nil, []error{ErrNoFrames}},
+		{"empty frame", []string{"a", ""}, []error{ErrEmptyFrame}},
+		{"widths", []string{".", "..", "..."}, []error{ErrFrameWidth, ErrFrameWidth}},
+		{"wide character", []string{"ab", "世"}, nil},
+		{"control character", []string{"\x1b[31ma", "b"}, []error{ErrControlCharacter}},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.frames)
+		if len(tt.want) == 0 {
+			if err != nil {
+				t.Errorf("%s: got %v, want no error", tt.name, err)
+			}
+			continue
+		}
+		var errs FrameErrors
+		if !errors.As(err, &errs) {
+			if !errors.Is(err, tt.want[0]) {
+				t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+			}
+			continue
+		}
+		if len(errs) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		for i, e := range errs {
+			if !errors.Is(e, tt.want[i]) {
+				t.Errorf("%s: got %v, want %v", tt.name, e, tt.want[i])
+			}
+		}
+	}
+}
+
+// TestValidateCharSets verifies that every character set only has frames
+// of differing widths, if anything, which padding fixes
+func TestValidateCharSets(t *testing.T) {
+	for i, cs := range CharSets {
+		var errs FrameErrors
+		if err := Validate(cs); err != nil && !errors.As(err, &errs) {
+			t.Errorf("CharSets[%d]: %v", i, err)
+		}
+		for _, e := range errs {
+			if !errors.Is(e, ErrFrameWidth) {
+				t.Errorf("CharSets[%d]: %v", i, e)
+			}
+		}
+		if err := Validate(Pad(cs)); err != nil {
+			t.Errorf("CharSets[%d] padded: %v", i, err)
+		}
+	}
+}
+
+// TestWithAutoPad verifies that the frames of the spinner are padded,
+// including those given to UpdateCharSet
+func TestWithAutoPad(t *testing.T) {
+	s := New(CharSets[26], time.Second, WithAutoPad())
+	if want := []string{".  ", ".. ", "..."}; !reflect.DeepEqual(s.chars, want) {
+		t.Errorf("got %q, want %q", s.chars, want)
+	}
+
+	s.UpdateCharSet(CharSets[77])
+	if want := []string{"▌ ", "▀ ", "▐▄"}; !reflect.DeepEqual(s.chars, want) {
+		t.Errorf("got %q, want %q", s.chars, want)
+	}
+	if CharSets[26][0] != "." {
+		t.Errorf("CharSets[26] was modified: %q", CharSets[26])
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spinner
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wideRanges holds the rune ranges rendered as two terminal cells. It
+// covers the East Asian wide and fullwidth blocks as well as the emoji
+// blocks used by the character sets.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x23E9, 0x23EC},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x26A1, 0x26A1},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26D4, 0x26D4},
+	{0x26EA, 0x26EA},
+	{0x26F2, 0x26F5},
+	{0x26FA, 0x26FA},
+	{0x26FD, 0x26FD},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x274E, 0x274E},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27B0, 0x27B0},
+	{0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50},
+	{0x2B55, 0x2B55},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A},
+	{0x1F200, 0x1F2FF},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F7E0, 0x1F7EB},
+	{0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of terminal cells the given rune occupies.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r == 0x200B || r == 0x200C || r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F):
+		return 0
+	case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r):
+		return 0
+	case r < 0x1100:
+		return 1
+	}
+	for _, rng := range wideRanges {
+		if r < rng[0] {
+			break
+		}
+		if r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// displayWidth returns the number of terminal cells needed to display
+// the given string, ignoring any ANSI escape sequences.
+func displayWidth(s string) int {
+	width := 0
+	ansi := false
+
+	for _, r := range s {
+		if ansi || isAnsiMarker(r) {
+			ansi = !isAnsiTerminator(r)
+		} else {
+			width += runeWidth(r)
+		}
+	}
+
+	return width
+}
+
+// truncateWidth shortens s so it is displayed in at most n cells, marking
+// the cut with an ellipsis. ANSI escape sequences take no cells and are
+// all kept, so a color reset after the cut still applies.
+func truncateWidth(s string, n int) string {
+	if displayWidth(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	width := 0
+	ansi, cut := false, false
+	for _, r := range s {
+		if ansi || isAnsiMarker(r) {
+			ansi = !isAnsiTerminator(r)
+			b.WriteRune(r)
+			continue
+		}
+		if cut {
+			continue
+		}
+		w := runeWidth(r)
+		if width+w > n-1 {
+			b.WriteString("…")
+			cut = true
+			continue
+		}
+		b.WriteRune(r)
+		width += w
+	}
+	return b.String()
+}
+
+// stripControl returns s without ANSI escape sequences and other control
+// characters, e.g. to show output of another program on a single line.
+func stripControl(s string) string {
+	var b strings.Builder
+	ansi := false
+	for _, r := range s {
+		switch {
+		case ansi || isAnsiMarker(r):
+			ansi = !isAnsiTerminator(r)
+		case r == '\t':
+			b.WriteByte(' ')
+		case unicode.IsControl(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}